@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/vanclief/compose/components/configurator"
+	"github.com/vanclief/compose/components/logger"
 	"github.com/vanclief/compose/integrations/aws/s3"
 	"github.com/vanclief/compose/integrations/aws/ses"
 	"github.com/vanclief/compose/integrations/promtail"
@@ -18,6 +19,22 @@ import (
 type BaseController struct {
 	Environment string
 	logWriter   io.Writer
+	log         logger.Logger
+}
+
+// WithLogger sets the Logger the controller and the clients it creates log to.
+// Defaults to logger.Default, which writes to the global zerolog logger set up by
+// WithZerolog and WithPromtail.
+func (c *BaseController) WithLogger(log logger.Logger) {
+	c.log = log
+}
+
+// Logger returns the Logger set with WithLogger, logger.Default if none.
+func (c *BaseController) Logger() logger.Logger {
+	if c.log == nil {
+		return logger.Default()
+	}
+	return c.log
 }
 
 func (c *BaseController) LoadEnvVarsAndConfig(envVarsOutput, configOutput any, configOpts ...configurator.Option) error {
@@ -61,7 +78,7 @@ func (c *BaseController) WithPromtail(params *promtail.WithPromtailParams) error
 
 	c.logWriter = writer
 	log.Logger = log.Output(writer)
-	log.Info().
+	c.Logger().Info().
 		Str("App", params.App).
 		Str("Environment", params.Environment).
 		Str("Host", params.PromtailHost).
@@ -74,7 +91,7 @@ func (c *BaseController) WithPromtail(params *promtail.WithPromtailParams) error
 }
 
 func (c *BaseController) WithSES(ctx context.Context, cfg *ses.Config, AWSSecretKey string) (*ses.Client, error) {
-	log.Info().
+	c.Logger().Info().
 		Str("Host", cfg.Region).
 		Str("AccessKey", cfg.AccessKeyID).
 		Str("Email Sender", cfg.EmailSender).
@@ -91,15 +108,14 @@ func (c *BaseController) WithSES(ctx context.Context, cfg *ses.Config, AWSSecret
 		ses.WithPushNotificationARN(cfg.PushNotificationARN),
 	)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to setup AWS Client")
-		os.Exit(1)
+		c.Logger().Fatal().Err(err).Msg("Failed to setup AWS Client")
 	}
 
 	return sesClient, nil
 }
 
 func (c *BaseController) WithS3(ctx context.Context, cfg *s3.Config, S3SecretKey string, opts ...s3.ClientOption) (*s3.Client, error) {
-	log.Info().
+	c.Logger().Info().
 		Str("Host", cfg.Region).
 		Str("Bucket", cfg.Bucket).
 		Str("AccessKey", cfg.AccessKeyID).
@@ -107,8 +123,7 @@ func (c *BaseController) WithS3(ctx context.Context, cfg *s3.Config, S3SecretKey
 
 	s3Client, err := s3.NewClient(ctx, cfg.Region, cfg.AccessKeyID, S3SecretKey, cfg.Bucket, opts...)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to setup S3 Client")
-		os.Exit(1)
+		c.Logger().Fatal().Err(err).Msg("Failed to setup S3 Client")
 	}
 
 	return s3Client, nil
@@ -1,23 +1,26 @@
 package ctrl
 
 import (
-	"github.com/rs/zerolog/log"
-	"github.com/uptrace/bun/extra/bundebug"
 	"github.com/vanclief/compose/drivers/databases/relational"
 	"github.com/vanclief/compose/drivers/databases/relational/postgres"
 	"github.com/vanclief/ez"
 )
 
 func (c *BaseController) WithPostgres(cfg *postgres.ConnectionConfig, models []interface{}, options ...relational.Option) (*relational.DB, error) {
+	if cfg.Logger == nil && c.log != nil {
+		withLogger := *cfg
+		withLogger.Logger = c.log
+		cfg = &withLogger
+	}
+
 	db, err := postgres.ConnectToDatabase(cfg)
 	if err != nil {
 		return nil, ez.Wrap(err)
 	}
 
 	if cfg.Verbose {
-		queryHook := bundebug.NewQueryHook(bundebug.WithVerbose(cfg.Verbose))
-		db.AddQueryHook(queryHook)
-		log.Info().Bool("Verbose", cfg.Verbose).Msg("Displaying database query logs")
+		db.AddQueryHook(relational.NewQueryHook(db.Logger(), cfg.Verbose))
+		db.Logger().Info().Bool("Verbose", cfg.Verbose).Msg("Displaying database query logs")
 	}
 
 	for _, option := range options {
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+)
+
+type (
+	fieldsKey struct{}
+	loggerKey struct{}
+)
+
+var (
+	defaultLogger  atomic.Pointer[Logger]
+	fallbackLogger = WithRedaction(globalZero{}, nil)
+)
+
+// SetDefault sets the Logger returned by FromContext for contexts without one.
+func SetDefault(l Logger) {
+	if l != nil {
+		defaultLogger.Store(&l)
+	}
+}
+
+// Default returns the Logger set with SetDefault. Until one is set it logs through the
+// global zerolog logger, so packages logging through Default keep writing where they used
+// to, redacting the fields matched by DefaultRedactor.
+func Default() Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return *l
+	}
+	return fallbackLogger
+}
+
+// WithFields returns a copy of ctx carrying the kv fields on top of the ones it already
+// carries. Loggers obtained with Ctx or FromContext add them to every event, which lets
+// logs from any layer be correlated, e.g. by request ID.
+func WithFields(ctx context.Context, kv ...any) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey{}, slices.Concat(ContextFields(ctx), kv))
+}
+
+// ContextFields returns the fields attached to ctx with WithFields.
+func ContextFields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	kv, _ := ctx.Value(fieldsKey{}).([]any)
+	return kv
+}
+
+// ContextField returns the value of key in the fields attached to ctx, the latest if
+// it was attached more than once.
+func ContextField(ctx context.Context, key string) (any, bool) {
+	kv := ContextFields(ctx)
+	for i := len(kv) - len(kv)%2 - 2; i >= 0; i -= 2 {
+		if kv[i] == key {
+			return kv[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// NewContext returns a copy of ctx carrying l, see FromContext.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the Logger carried by ctx, or the default one, with the fields of ctx attached.
+func FromContext(ctx context.Context) Logger {
+	l := Default()
+	if ctx != nil {
+		if ctxLogger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+			l = ctxLogger
+		}
+	}
+	return l.Ctx(ctx)
+}
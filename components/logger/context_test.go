@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	ctx := WithFields(context.Background(), "request_id", "req-1")
+	child := WithFields(ctx, "job_id", "export")
+
+	// the parent context is left untouched
+	require.Equal(t, []any{"request_id", "req-1"}, ContextFields(ctx))
+	require.Equal(t, []any{"request_id", "req-1", "job_id", "export"}, ContextFields(child))
+
+	id, ok := ContextField(WithFields(child, "request_id", "req-2"), "request_id")
+	require.True(t, ok)
+	require.Equal(t, "req-2", id)
+	_, ok = ContextField(child, "user_id")
+	require.False(t, ok)
+
+	var zeroBuf, slogBuf bytes.Buffer
+	loggers := map[string]Logger{
+		"zerolog": NewZero(zerolog.New(&zeroBuf)),
+		"slog":    NewSlog(slog.New(slog.NewJSONHandler(&slogBuf, nil))),
+	}
+	buffers := map[string]*bytes.Buffer{"zerolog": &zeroBuf, "slog": &slogBuf}
+
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			FromContext(NewContext(child, l)).Info().Msg("hello")
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buffers[name].Bytes(), &entry))
+			require.Equal(t, "req-1", entry["request_id"])
+			require.Equal(t, "export", entry["job_id"])
+		})
+	}
+
+	// without a logger in the context the default one is used
+	SetDefault(Noop{})
+	t.Cleanup(func() { defaultLogger.Store(nil) })
+	require.Equal(t, Noop{}, FromContext(child))
+}
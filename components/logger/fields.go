@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+// ObjectMarshaler is implemented by types that log themselves as a nested object
+// with Event.Object, adding their fields to e.
+type ObjectMarshaler interface {
+	MarshalLogObject(e Event)
+}
+
+// Dict returns an Event collecting fields to nest under a key with Event.Dict:
+//
+//	log.Info().Dict("job", logger.Dict().Str("id", id).Int("attempts", n)).Msg("Job done")
+//
+// Its Msg does nothing.
+func Dict() Event {
+	return &dictEvent{}
+}
+
+// dictEvent records fields to replay them on the Event of an adapter.
+type dictEvent struct {
+	fields []field
+}
+
+type field struct {
+	key   string
+	value any
+}
+
+// wrappers of the values that could be mistaken for another field type
+type (
+	anyValue      struct{ v any }
+	errValue      struct{ err error }
+	stringerValue struct{ s fmt.Stringer }
+)
+
+func (d *dictEvent) add(k string, v any) Event { d.fields = append(d.fields, field{k, v}); return d }
+
+func (d *dictEvent) Str(k, v string) Event                   { return d.add(k, v) }
+func (d *dictEvent) Strs(k string, v []string) Event         { return d.add(k, v) }
+func (d *dictEvent) Int(k string, v int) Event               { return d.add(k, v) }
+func (d *dictEvent) Ints(k string, v []int) Event            { return d.add(k, v) }
+func (d *dictEvent) Int64(k string, v int64) Event           { return d.add(k, v) }
+func (d *dictEvent) Uint64(k string, v uint64) Event         { return d.add(k, v) }
+func (d *dictEvent) Float64(k string, v float64) Event       { return d.add(k, v) }
+func (d *dictEvent) Bool(k string, v bool) Event             { return d.add(k, v) }
+func (d *dictEvent) Time(k string, t time.Time) Event        { return d.add(k, t) }
+func (d *dictEvent) Dur(k string, v time.Duration) Event     { return d.add(k, v) }
+func (d *dictEvent) Bytes(k string, b []byte) Event          { return d.add(k, b) }
+func (d *dictEvent) Stringer(k string, v fmt.Stringer) Event { return d.add(k, stringerValue{v}) }
+func (d *dictEvent) Any(k string, v any) Event               { return d.add(k, anyValue{v}) }
+func (d *dictEvent) Dict(k string, dict Event) Event         { return d.add(k, asDict(dict)) }
+func (d *dictEvent) Object(k string, obj ObjectMarshaler) Event {
+	return d.add(k, objectDict(obj))
+}
+func (d *dictEvent) Msg(string) {}
+
+func (d *dictEvent) Err(err error) Event {
+	if err != nil {
+		d.add("error", errValue{err})
+	}
+	return d
+}
+
+// replay adds the recorded fields to e.
+func (d *dictEvent) replay(e Event) Event {
+	for _, f := range d.fields {
+		switch v := f.value.(type) {
+		case string:
+			e = e.Str(f.key, v)
+		case []string:
+			e = e.Strs(f.key, v)
+		case int:
+			e = e.Int(f.key, v)
+		case []int:
+			e = e.Ints(f.key, v)
+		case int64:
+			e = e.Int64(f.key, v)
+		case uint64:
+			e = e.Uint64(f.key, v)
+		case float64:
+			e = e.Float64(f.key, v)
+		case bool:
+			e = e.Bool(f.key, v)
+		case time.Time:
+			e = e.Time(f.key, v)
+		case time.Duration:
+			e = e.Dur(f.key, v)
+		case []byte:
+			e = e.Bytes(f.key, v)
+		case stringerValue:
+			e = e.Stringer(f.key, v.s)
+		case anyValue:
+			e = e.Any(f.key, v.v)
+		case errValue:
+			e = e.Err(v.err)
+		case *dictEvent:
+			e = e.Dict(f.key, v)
+		}
+	}
+	return e
+}
+
+// Replay adds the fields of an Event created by Dict to e, for Event implementations
+// outside this package. Other Events add nothing.
+func Replay(dict, e Event) Event {
+	return asDict(dict).replay(e)
+}
+
+// asDict returns the fields of an Event created by Dict, none for any other Event.
+func asDict(e Event) *dictEvent {
+	if d, ok := e.(*dictEvent); ok {
+		return d
+	}
+	return &dictEvent{}
+}
+
+func objectDict(obj ObjectMarshaler) *dictEvent {
+	d := &dictEvent{}
+	if obj != nil {
+		obj.MarshalLogObject(d)
+	}
+	return d
+}
+
+// stringOf returns the String of s, "<nil>" for nil values.
+func stringOf(s fmt.Stringer) (str string) {
+	defer func() {
+		// String of a nil pointer
+		if recover() != nil {
+			str = "<nil>"
+		}
+	}()
+
+	if s == nil {
+		return "<nil>"
+	}
+	return s.String()
+}
+
+// errFields adds the code, the op chain and the messages of the wrapped errors of an
+// ez error to e, as error_code, error_ops and error_chain. Other errors add nothing.
+func errFields(e Event, err error) Event {
+	ezErr, ok := err.(*ez.Error)
+	if !ok {
+		return e
+	}
+
+	var ops, chain []string
+	for next := error(ezErr); next != nil; {
+		wrapped, ok := next.(*ez.Error)
+		if !ok {
+			chain = append(chain, next.Error())
+			break
+		}
+
+		if wrapped.Op != "" {
+			ops = append(ops, wrapped.Op)
+		}
+		if wrapped.Message != "" {
+			chain = append(chain, wrapped.Message)
+		}
+		next = wrapped.Err
+	}
+
+	e = e.Str("error_code", ez.ErrorCode(err))
+	if len(ops) > 0 {
+		e = e.Strs("error_ops", ops)
+	}
+	if len(chain) > 0 {
+		e = e.Strs("error_chain", chain)
+	}
+	return e
+}
+
+var _ Event = (*dictEvent)(nil)
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/ez"
+)
+
+type testJob struct {
+	ID       string
+	Attempts int
+	Token    string
+}
+
+func (j testJob) MarshalLogObject(e Event) {
+	e.Str("id", j.ID).Int("attempts", j.Attempts).Str("token", j.Token)
+}
+
+func TestEventFields(t *testing.T) {
+	var zeroBuf, slogBuf bytes.Buffer
+	loggers := map[string]Logger{
+		"zerolog": NewZero(zerolog.New(&zeroBuf)),
+		"slog":    NewSlog(slog.New(slog.NewJSONHandler(&slogBuf, nil))),
+	}
+	buffers := map[string]*bytes.Buffer{"zerolog": &zeroBuf, "slog": &slogBuf}
+
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			buf := buffers[name]
+			l = WithRedaction(l, nil)
+
+			var nilIP *net.IPNet
+			l.Info().
+				Strs("queues", []string{"default", "mail"}).
+				Ints("shards", []int{1, 2}).
+				Stringer("ip", net.IPv4(10, 0, 0, 1)).
+				Stringer("network", nilIP).
+				Dict("request", Dict().Str("method", "GET").Dict("headers", Dict().Str("authorization", "Bearer x"))).
+				Object("job", testJob{ID: "export", Attempts: 2, Token: "t0k3n"}).
+				Msg("Job done")
+
+			lines := jsonLines(t, buf)
+			require.Len(t, lines, 1)
+			line := lines[0]
+
+			require.Equal(t, []any{"default", "mail"}, line["queues"])
+			require.Equal(t, []any{1.0, 2.0}, line["shards"])
+			require.Equal(t, "10.0.0.1", line["ip"])
+			require.Equal(t, "<nil>", line["network"])
+			require.Equal(t, map[string]any{
+				"method":  "GET",
+				"headers": map[string]any{"authorization": Redacted},
+			}, line["request"])
+			require.Equal(t, map[string]any{"id": "export", "attempts": 2.0, "token": Redacted}, line["job"])
+
+			cause := errors.New("connection refused")
+			err := ez.Wrap(ez.New(ez.EINTERNAL, "Could not export the job", cause))
+			l.Error().Err(err).Msg("Job failed")
+			l.Error().Err(cause).Msg("Job failed")
+
+			lines = jsonLines(t, buf)
+			require.Len(t, lines, 2)
+
+			require.Equal(t, ez.EINTERNAL, lines[0]["error_code"])
+			require.Len(t, lines[0]["error_ops"], 2)
+			require.Equal(t, []any{"Could not export the job", "connection refused"}, lines[0]["error_chain"])
+			require.NotEmpty(t, lines[0]["error"])
+
+			require.Equal(t, "connection refused", lines[1]["error"])
+			require.NotContains(t, lines[1], "error_code")
+		})
+	}
+}
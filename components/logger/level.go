@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/vanclief/ez"
+)
+
+// Level is the severity of an event. The values match zerolog's levels.
+type Level int8
+
+const (
+	LevelTrace Level = iota - 1
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal    // exits the program after the event is logged
+	LevelDisabled // logs nothing, only meaningful as a minimum level
+)
+
+var levelNames = map[Level]string{
+	LevelTrace:    "trace",
+	LevelDebug:    "debug",
+	LevelInfo:     "info",
+	LevelWarn:     "warn",
+	LevelError:    "error",
+	LevelFatal:    "fatal",
+	LevelDisabled: "disabled",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", l)
+}
+
+// ParseLevel parses a level name as returned by Level.String, ignoring case.
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for level, name := range levelNames {
+		if name == s {
+			return level, nil
+		}
+	}
+
+	msg := fmt.Sprintf("%q is not a valid log level", s)
+	return 0, ez.New(ez.EINVALID, msg, nil)
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	*l = level
+	return nil
+}
+
+// LevelVar is a minimum Level that can be changed while loggers use it.
+// A logger and the loggers derived from it with With and Ctx share one.
+type LevelVar struct {
+	v atomic.Int32
+}
+
+// NewLevelVar returns a LevelVar set to level.
+func NewLevelVar(level Level) *LevelVar {
+	v := &LevelVar{}
+	v.Set(level)
+	return v
+}
+
+// Level returns the current level.
+func (v *LevelVar) Level() Level {
+	return Level(v.v.Load())
+}
+
+// Set changes the level.
+func (v *LevelVar) Set(level Level) {
+	v.v.Store(int32(level))
+}
+
+// Enabled reports whether events at level pass the minimum level.
+func (v *LevelVar) Enabled(level Level) bool {
+	return level >= v.Level() && level < LevelDisabled
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	for level := LevelTrace; level <= LevelDisabled; level++ {
+		parsed, err := ParseLevel(strings.ToUpper(level.String()))
+		require.NoError(t, err)
+		require.Equal(t, level, parsed)
+	}
+
+	_, err := ParseLevel("verbose")
+	require.Error(t, err)
+
+	var levels map[string]Level
+	require.NoError(t, json.Unmarshal([]byte(`{"db":"warn"}`), &levels))
+	require.Equal(t, map[string]Level{"db": LevelWarn}, levels)
+}
+
+func TestLevels(t *testing.T) {
+	var zeroBuf, slogBuf bytes.Buffer
+	loggers := map[string]Logger{
+		"zerolog": NewZero(zerolog.New(&zeroBuf).Level(zerolog.DebugLevel)),
+		"slog":    NewSlog(slog.New(slog.NewJSONHandler(&slogBuf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	}
+	buffers := map[string]*bytes.Buffer{"zerolog": &zeroBuf, "slog": &slogBuf}
+
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			buf := buffers[name]
+
+			// the backend filters trace events
+			require.False(t, l.Enabled(LevelTrace))
+			require.Equal(t, noopEvent{}, l.Trace())
+			require.True(t, l.Enabled(LevelDebug))
+
+			// the level is shared with derived loggers
+			derived := l.With("job_id", "export")
+			l.SetLevel(LevelWarn)
+			require.Equal(t, LevelWarn, derived.Level())
+			require.False(t, derived.Enabled(LevelInfo))
+			require.Equal(t, noopEvent{}, derived.Info())
+
+			derived.Info().Msg("dropped")
+			derived.Warn().Msg("kept")
+			require.NotContains(t, buf.String(), "dropped")
+			require.Contains(t, buf.String(), "kept")
+
+			l.SetLevel(LevelDisabled)
+			require.False(t, l.Enabled(LevelFatal))
+		})
+	}
+}
+
+func TestLowerLevel(t *testing.T) {
+	var zeroBuf, slogBuf bytes.Buffer
+	loggers := map[string]Logger{
+		"zerolog": NewZero(zerolog.New(&zeroBuf).Level(zerolog.InfoLevel)),
+		"slog":    NewSlog(slog.New(slog.NewJSONHandler(&slogBuf, &slog.HandlerOptions{Level: slog.LevelInfo}))),
+	}
+	buffers := map[string]*bytes.Buffer{"zerolog": &zeroBuf, "slog": &slogBuf}
+
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			buf := buffers[name]
+
+			// starts at the level of the backend
+			require.Equal(t, LevelInfo, l.Level())
+			l.Debug().Msg("dropped")
+			require.Empty(t, buf.String())
+
+			l.SetLevel(LevelTrace)
+			l.With("job_id", "export").Debug().Msg("debug")
+			l.Trace().Msg("trace")
+
+			lines := jsonLines(t, buf)
+			require.Len(t, lines, 2)
+			msgKey := map[string]string{"zerolog": zerolog.MessageFieldName, "slog": slog.MessageKey}[name]
+			require.Equal(t, "debug", lines[0][msgKey])
+			require.Equal(t, "export", lines[0]["job_id"])
+			require.Equal(t, "trace", lines[1][msgKey])
+		})
+	}
+}
+
+func TestZerologGlobalLevel(t *testing.T) {
+	globalLogger, global := log.Logger, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		log.Logger = globalLogger
+		zerolog.SetGlobalLevel(global)
+		globalLevel.Set(LevelTrace)
+	})
+
+	var buf bytes.Buffer
+	log.Logger = zerolog.New(&buf)
+	wrapped := NewZero(zerolog.New(&buf))
+
+	// changed after the loggers were created
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	for _, l := range []Logger{Default(), wrapped} {
+		require.Equal(t, LevelInfo, l.Level())
+		require.False(t, l.Enabled(LevelDebug))
+
+		// SetLevel can't go below zerolog's level
+		l.SetLevel(LevelTrace)
+		l.Debug().Msg("dropped")
+		l.With("job_id", "export").Debug().Msg("dropped")
+		require.Empty(t, buf.String())
+	}
+
+	// the level of log.Logger applies to Default
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+	log.Logger = zerolog.New(&buf).Level(zerolog.WarnLevel)
+	Default().Info().Msg("dropped")
+	require.Empty(t, buf.String())
+	require.Equal(t, LevelWarn, Default().Level())
+
+	// SetLevel can raise it
+	Default().SetLevel(LevelError)
+	Default().Warn().Msg("dropped")
+	require.Empty(t, buf.String())
+	Default().Error().Msg("logged")
+	require.Len(t, jsonLines(t, &buf), 1)
+}
+
+func TestDisabledFatal(t *testing.T) {
+	code, osExit := 0, exit
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = osExit })
+
+	var buf bytes.Buffer
+	loggers := map[string]Logger{
+		"zerolog": NewZero(zerolog.New(&buf)),
+		"slog":    NewSlog(slog.New(slog.NewJSONHandler(&buf, nil))),
+		"noop":    Noop{},
+		"redact":  WithRedaction(NewZero(zerolog.New(&buf)), nil),
+	}
+
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			code = 0
+			l.SetLevel(LevelDisabled)
+			l.Fatal().Err(errors.New("boom")).Msg("cannot start")
+
+			require.Equal(t, 1, code)
+			require.Empty(t, buf.String())
+		})
+	}
+}
+
+func TestSlogFatal(t *testing.T) {
+	code, osExit := 0, exit
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = osExit })
+
+	var buf bytes.Buffer
+	NewSlog(slog.New(slog.NewJSONHandler(&buf, nil))).Fatal().Msg("cannot start")
+
+	require.Equal(t, 1, code)
+	require.Contains(t, buf.String(), `"level":"ERROR+4"`)
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	db := reg.Register("db", NewZero(zerolog.Nop()))
+	reg.Register("http", NewSlog(slog.Default()))
+
+	require.Equal(t, []string{"db", "http"}, reg.Names())
+	require.NoError(t, reg.SetLevel("db", LevelError))
+	require.Equal(t, LevelError, db.Level())
+	// slog's default handler starts at info
+	require.Equal(t, map[string]Level{"db": LevelError, "http": LevelInfo}, reg.Levels())
+
+	require.Error(t, reg.SetLevel("queue", LevelDebug))
+}
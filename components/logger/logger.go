@@ -1,23 +1,46 @@
 package logger
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// exit ends the program after a fatal event, replaced in tests.
+var exit = os.Exit
 
 // Logger produces Event builders at specific levels and can attach context fields.
 // Implementations MUST be safe for concurrent use.
 type Logger interface {
 	With(kv ...any) Logger
+	// Ctx returns a Logger with the fields attached to ctx by WithFields.
+	Ctx(ctx context.Context) Logger
+
+	// Enabled reports whether events at level are logged. Events of disabled levels
+	// are noops, so they cost nothing to build.
+	Enabled(level Level) bool
+	// SetLevel sets the minimum level, shared with the loggers derived with With and Ctx.
+	SetLevel(level Level)
+	// Level returns the minimum level.
+	Level() Level
 
+	Trace() Event
 	Debug() Event
 	Info() Event
 	Warn() Event
 	Error() Event
+	// Fatal events exit the program with status 1 once logged.
+	Fatal() Event
 }
 
 // Event is a one shot builder. Callers add fields, then finish with Msg following Zerolog style
 type Event interface {
 	// Field helpers
 	Str(key, val string) Event
+	Strs(key string, vals []string) Event
 	Int(key string, val int) Event
+	Ints(key string, vals []int) Event
 	Int64(key string, val int64) Event
 	Uint64(key string, val uint64) Event
 	Float64(key string, val float64) Event
@@ -25,9 +48,17 @@ type Event interface {
 	Time(key string, t time.Time) Event
 	Dur(key string, d time.Duration) Event
 	Bytes(key string, b []byte) Event
+	Stringer(key string, val fmt.Stringer) Event
+	// Err adds err under "error"; ez errors also add error_code, error_ops and error_chain.
 	Err(err error) Event
 	Any(key string, v any) Event
 
+	// Nesting
+	// Dict nests the fields of an Event created by logger.Dict under key.
+	Dict(key string, dict Event) Event
+	// Object nests the fields added by obj under key.
+	Object(key string, obj ObjectMarshaler) Event
+
 	// Finalize
 	Msg(msg string)
 }
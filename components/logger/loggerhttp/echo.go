@@ -0,0 +1,131 @@
+package loggerhttp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/vanclief/compose/components/logger"
+)
+
+// EchoLogger is an echo.Logger logging to a logger.Logger, set it as the Logger of an
+// Echo instance so its messages go through the app's backend:
+//
+//	e.Logger = loggerhttp.NewEchoLogger(log)
+//
+// Its level is the one of the logger.Logger. Output returns a writer logging each line
+// as an error, e.g. for the errors of the http.Server; SetOutput and SetHeader are ignored.
+type EchoLogger struct {
+	l      logger.Logger
+	prefix string
+}
+
+// NewEchoLogger returns an EchoLogger logging to l.
+func NewEchoLogger(l logger.Logger) *EchoLogger {
+	return &EchoLogger{l: l}
+}
+
+func (el *EchoLogger) Output() io.Writer   { return lineWriter{el} }
+func (el *EchoLogger) SetOutput(io.Writer) {}
+func (el *EchoLogger) SetHeader(string)    {}
+func (el *EchoLogger) Prefix() string      { return el.prefix }
+func (el *EchoLogger) SetPrefix(p string)  { el.prefix = p }
+
+func (el *EchoLogger) Level() log.Lvl {
+	switch el.l.Level() {
+	case logger.LevelTrace, logger.LevelDebug:
+		return log.DEBUG
+	case logger.LevelInfo:
+		return log.INFO
+	case logger.LevelWarn:
+		return log.WARN
+	case logger.LevelError, logger.LevelFatal:
+		return log.ERROR
+	}
+	return log.OFF
+}
+
+func (el *EchoLogger) SetLevel(v log.Lvl) {
+	switch v {
+	case log.DEBUG:
+		el.l.SetLevel(logger.LevelDebug)
+	case log.INFO:
+		el.l.SetLevel(logger.LevelInfo)
+	case log.WARN:
+		el.l.SetLevel(logger.LevelWarn)
+	case log.ERROR:
+		el.l.SetLevel(logger.LevelError)
+	case log.OFF:
+		el.l.SetLevel(logger.LevelDisabled)
+	}
+}
+
+func (el *EchoLogger) Print(i ...any)            { el.print(el.l.Info(), i) }
+func (el *EchoLogger) Printf(f string, a ...any) { el.printf(el.l.Info(), f, a) }
+func (el *EchoLogger) Printj(j log.JSON)         { el.json(el.l.Info(), j) }
+func (el *EchoLogger) Debug(i ...any)            { el.print(el.l.Debug(), i) }
+func (el *EchoLogger) Debugf(f string, a ...any) { el.printf(el.l.Debug(), f, a) }
+func (el *EchoLogger) Debugj(j log.JSON)         { el.json(el.l.Debug(), j) }
+func (el *EchoLogger) Info(i ...any)             { el.print(el.l.Info(), i) }
+func (el *EchoLogger) Infof(f string, a ...any)  { el.printf(el.l.Info(), f, a) }
+func (el *EchoLogger) Infoj(j log.JSON)          { el.json(el.l.Info(), j) }
+func (el *EchoLogger) Warn(i ...any)             { el.print(el.l.Warn(), i) }
+func (el *EchoLogger) Warnf(f string, a ...any)  { el.printf(el.l.Warn(), f, a) }
+func (el *EchoLogger) Warnj(j log.JSON)          { el.json(el.l.Warn(), j) }
+func (el *EchoLogger) Error(i ...any)            { el.print(el.l.Error(), i) }
+func (el *EchoLogger) Errorf(f string, a ...any) { el.printf(el.l.Error(), f, a) }
+func (el *EchoLogger) Errorj(j log.JSON)         { el.json(el.l.Error(), j) }
+func (el *EchoLogger) Fatal(i ...any)            { el.print(el.l.Fatal(), i) }
+func (el *EchoLogger) Fatalf(f string, a ...any) { el.printf(el.l.Fatal(), f, a) }
+func (el *EchoLogger) Fatalj(j log.JSON)         { el.json(el.l.Fatal(), j) }
+
+// Panic methods log an error and then panic with the message, as echo's logger does.
+func (el *EchoLogger) Panic(i ...any)            { el.panic(fmt.Sprint(i...)) }
+func (el *EchoLogger) Panicf(f string, a ...any) { el.panic(fmt.Sprintf(f, a...)) }
+
+func (el *EchoLogger) Panicj(j log.JSON) {
+	el.json(el.l.Error(), j)
+	panic(j)
+}
+
+func (el *EchoLogger) panic(msg string) {
+	el.msg(el.l.Error(), msg)
+	panic(msg)
+}
+
+func (el *EchoLogger) print(e logger.Event, i []any)            { el.msg(e, fmt.Sprint(i...)) }
+func (el *EchoLogger) printf(e logger.Event, f string, a []any) { el.msg(e, fmt.Sprintf(f, a...)) }
+
+func (el *EchoLogger) msg(e logger.Event, msg string) {
+	if el.prefix != "" {
+		e = e.Str("prefix", el.prefix)
+	}
+	e.Msg(msg)
+}
+
+// json logs the fields of j, its "message" field as the message.
+func (el *EchoLogger) json(e logger.Event, j log.JSON) {
+	msg, _ := j["message"].(string)
+	for k, v := range j {
+		if k != "message" {
+			e = e.Any(k, v)
+		}
+	}
+	el.msg(e, msg)
+}
+
+// lineWriter logs every line written to it as an error.
+type lineWriter struct{ el *EchoLogger }
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if len(line) > 0 {
+			w.el.msg(w.el.l.Error(), string(line))
+		}
+	}
+	return len(p), nil
+}
+
+var _ echo.Logger = (*EchoLogger)(nil)
@@ -0,0 +1,34 @@
+package loggerhttp
+
+import (
+	"errors"
+	stdlog "log"
+	"testing"
+
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger/logtest"
+)
+
+func TestEchoLogger(t *testing.T) {
+	rec := logtest.New()
+	el := NewEchoLogger(rec)
+	el.SetPrefix("echo")
+
+	el.SetLevel(log.WARN)
+	require.Equal(t, log.WARN, el.Level())
+	require.Equal(t, logtest.LevelWarn, rec.Level())
+
+	el.Info("dropped")
+	el.Warnf("slow request: %dms", 1200)
+	el.Errorj(log.JSON{"message": "Request failed", "status": 500})
+	stdlog.New(el.Output(), "", 0).Println(errors.New("http: TLS handshake error"))
+
+	require.Len(t, rec.Entries(), 3, rec.String())
+	require.True(t, rec.HasEntry(logtest.LevelWarn, "slow request: 1200ms", "prefix", "echo"), rec.String())
+	require.True(t, rec.HasEntry(logtest.LevelError, "Request failed", "status", 500), rec.String())
+	require.True(t, rec.HasEntry(logtest.LevelError, "http: TLS handshake error"), rec.String())
+
+	require.Panics(t, func() { el.Panicf("bad route %q", "/x") })
+	require.True(t, rec.HasEntry(logtest.LevelError, `bad route "/x"`), rec.String())
+}
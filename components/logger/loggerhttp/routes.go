@@ -0,0 +1,89 @@
+// Package loggerhttp exposes the levels of a logger.Registry over an Echo route group
+// and adapts echo's logger onto a logger.Logger.
+package loggerhttp
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vanclief/compose/components/logger"
+	"github.com/vanclief/compose/components/rest/handler"
+	"github.com/vanclief/ez"
+)
+
+// LevelRequest changes the level of a logger.
+type LevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LevelResponse is the level of a logger.
+type LevelResponse struct {
+	Name  string       `json:"name"`
+	Level logger.Level `json:"level"`
+}
+
+// Register mounts the level routes of reg on g:
+//
+//	GET /       list the level of every logger
+//	GET /:name  get the level of a logger
+//	PUT /:name  set the level of a logger, e.g. {"level": "debug"}
+//
+// The routes perform no authentication; protect g with the app's middleware.
+func Register(g *echo.Group, reg *logger.Registry) {
+	r := &routes{reg: reg}
+
+	g.GET("", r.list)
+	g.GET("/:name", r.get)
+	g.PUT("/:name", r.set)
+}
+
+type routes struct {
+	reg *logger.Registry
+}
+
+func (r *routes) list(c echo.Context) error {
+	levels := r.reg.Levels()
+
+	response := make([]LevelResponse, 0, len(levels))
+	for _, name := range r.reg.Names() {
+		if level, ok := levels[name]; ok {
+			response = append(response, LevelResponse{Name: name, Level: level})
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (r *routes) get(c echo.Context) error {
+	name := c.Param("name")
+	level, err := r.reg.Level(name)
+	if err != nil {
+		return manageError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, LevelResponse{Name: name, Level: level})
+}
+
+func (r *routes) set(c echo.Context) error {
+	request := new(LevelRequest)
+	if err := c.Bind(request); err != nil {
+		return manageError(c, ez.New(ez.EINVALID, "Could not parse the request body", err))
+	}
+
+	level, err := logger.ParseLevel(request.Level)
+	if err != nil {
+		return manageError(c, err)
+	}
+
+	if err := r.reg.SetLevel(c.Param("name"), level); err != nil {
+		return manageError(c, err)
+	}
+
+	return r.get(c)
+}
+
+// manageError writes err with the same body the rest handler uses.
+func manageError(c echo.Context, err error) error {
+	stdErr := handler.StandardError{Code: ez.ErrorCode(err), Message: ez.ErrorMessage(err)}
+	return c.JSON(ez.ErrorToHTTPStatus(err), handler.ErrorResponse{Error: stdErr})
+}
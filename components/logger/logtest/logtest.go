@@ -0,0 +1,279 @@
+// Package logtest provides a logger.Logger that records every event in memory, so
+// tests can assert what was logged.
+package logtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vanclief/compose/components/logger"
+)
+
+// Level is the level of an Entry.
+type Level = logger.Level
+
+const (
+	LevelTrace = logger.LevelTrace
+	LevelDebug = logger.LevelDebug
+	LevelInfo  = logger.LevelInfo
+	LevelWarn  = logger.LevelWarn
+	LevelError = logger.LevelError
+	LevelFatal = logger.LevelFatal
+)
+
+// Entry is a recorded event.
+type Entry struct {
+	Level   Level
+	Message string
+	Fields  map[string]any // added to the event, errors under "error"
+	Context map[string]any // added with With or carried by the context given to Ctx
+}
+
+// Field returns the value of key in the event fields, or else in the context.
+func (e Entry) Field(key string) (any, bool) {
+	if v, ok := e.Fields[key]; ok {
+		return v, true
+	}
+	v, ok := e.Context[key]
+	return v, ok
+}
+
+// String formats the entry like "info Job done attempts=2 job_id=export".
+func (e Entry) String() string {
+	fields := make(map[string]any, len(e.Context)+len(e.Fields))
+	for k, v := range e.Context {
+		fields[k] = v
+	}
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(e.Level.String() + " " + e.Message)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return b.String()
+}
+
+// Matcher matches a field value in HasEntry.
+type Matcher func(v any) bool
+
+// Present matches any value, to only check that a field was logged.
+func Present() Matcher {
+	return func(any) bool { return true }
+}
+
+// Contains matches values whose string form contains substr, e.g. error messages.
+func Contains(substr string) Matcher {
+	return func(v any) bool { return strings.Contains(fmt.Sprint(v), substr) }
+}
+
+// Logger is a logger.Logger recording every event. Loggers derived with With and Ctx
+// record to the same entries. Fatal events are recorded without exiting.
+// Safe for concurrent use.
+type Logger struct {
+	rec     *recorder
+	level   *logger.LevelVar
+	context []any
+}
+
+type recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New returns a Logger with no entries.
+func New() *Logger {
+	return &Logger{rec: &recorder{}, level: logger.NewLevelVar(LevelTrace)}
+}
+
+// With implements logger.Logger.
+func (l *Logger) With(kv ...any) logger.Logger {
+	return &Logger{rec: l.rec, level: l.level, context: append(l.context[:len(l.context):len(l.context)], kv...)}
+}
+
+// Ctx implements logger.Logger.
+func (l *Logger) Ctx(ctx context.Context) logger.Logger {
+	return l.With(logger.ContextFields(ctx)...)
+}
+
+// Enabled implements logger.Logger.
+func (l *Logger) Enabled(level Level) bool { return l.level.Enabled(level) }
+
+// SetLevel implements logger.Logger.
+func (l *Logger) SetLevel(level Level) { l.level.Set(level) }
+
+// Level implements logger.Logger.
+func (l *Logger) Level() Level { return l.level.Level() }
+
+// Trace implements logger.Logger.
+func (l *Logger) Trace() logger.Event { return l.event(LevelTrace) }
+
+// Debug implements logger.Logger.
+func (l *Logger) Debug() logger.Event { return l.event(LevelDebug) }
+
+// Info implements logger.Logger.
+func (l *Logger) Info() logger.Event { return l.event(LevelInfo) }
+
+// Warn implements logger.Logger.
+func (l *Logger) Warn() logger.Event { return l.event(LevelWarn) }
+
+// Error implements logger.Logger.
+func (l *Logger) Error() logger.Event { return l.event(LevelError) }
+
+// Fatal implements logger.Logger.
+func (l *Logger) Fatal() logger.Event { return l.event(LevelFatal) }
+
+func (l *Logger) event(level Level) logger.Event {
+	if !l.Enabled(level) {
+		return logger.Noop{}.Debug()
+	}
+	return &event{l: l, entry: Entry{Level: level, Fields: map[string]any{}}}
+}
+
+// Entries returns the recorded entries in the order they were logged.
+func (l *Logger) Entries() []Entry {
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+	return append([]Entry(nil), l.rec.entries...)
+}
+
+// Reset drops the recorded entries.
+func (l *Logger) Reset() {
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+	l.rec.entries = nil
+}
+
+// Find returns the entries at level with message msg, and the kv fields.
+// A field value is matched with errors.Is for errors, by calling it for a Matcher,
+// and by equality otherwise.
+func (l *Logger) Find(level Level, msg string, kv ...any) []Entry {
+	var found []Entry
+	for _, e := range l.Entries() {
+		if e.Level == level && e.Message == msg && matches(e, kv) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// HasEntry reports whether an entry at level with message msg and the kv fields was logged, see Find.
+func (l *Logger) HasEntry(level Level, msg string, kv ...any) bool {
+	return len(l.Find(level, msg, kv...)) > 0
+}
+
+// String lists the recorded entries one per line, to print when an assertion fails.
+func (l *Logger) String() string {
+	var b strings.Builder
+	for _, e := range l.Entries() {
+		b.WriteString(e.String() + "\n")
+	}
+	return b.String()
+}
+
+func (l *Logger) record(e Entry) {
+	if len(l.context) > 0 {
+		e.Context = make(map[string]any, len(l.context)/2)
+		for i := 0; i+1 < len(l.context); i += 2 {
+			if k, ok := l.context[i].(string); ok {
+				e.Context[k] = l.context[i+1]
+			}
+		}
+	}
+
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+	l.rec.entries = append(l.rec.entries, e)
+}
+
+func matches(e Entry, kv []any) bool {
+	for i := 0; i+1 < len(kv); i += 2 {
+		k, _ := kv[i].(string)
+		v, ok := e.Field(k)
+		if !ok || !matchValue(v, kv[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchValue(got, want any) bool {
+	switch want := want.(type) {
+	case Matcher:
+		return want(got)
+	case func(any) bool:
+		return want(got)
+	case error:
+		err, ok := got.(error)
+		return ok && errors.Is(err, want)
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+type event struct {
+	l     *Logger
+	entry Entry
+}
+
+func (e *event) add(k string, v any) logger.Event { e.entry.Fields[k] = v; return e }
+
+func (e *event) Str(k, v string) logger.Event               { return e.add(k, v) }
+func (e *event) Int(k string, v int) logger.Event           { return e.add(k, v) }
+func (e *event) Int64(k string, v int64) logger.Event       { return e.add(k, v) }
+func (e *event) Uint64(k string, v uint64) logger.Event     { return e.add(k, v) }
+func (e *event) Float64(k string, v float64) logger.Event   { return e.add(k, v) }
+func (e *event) Bool(k string, v bool) logger.Event         { return e.add(k, v) }
+func (e *event) Time(k string, t time.Time) logger.Event    { return e.add(k, t) }
+func (e *event) Dur(k string, d time.Duration) logger.Event { return e.add(k, d) }
+func (e *event) Bytes(k string, b []byte) logger.Event      { return e.add(k, b) }
+func (e *event) Any(k string, v any) logger.Event           { return e.add(k, v) }
+func (e *event) Strs(k string, v []string) logger.Event     { return e.add(k, v) }
+func (e *event) Ints(k string, v []int) logger.Event        { return e.add(k, v) }
+
+func (e *event) Stringer(k string, v fmt.Stringer) logger.Event { return e.add(k, fmt.Sprint(v)) }
+
+// Dict records the nested fields as a map[string]any.
+func (e *event) Dict(k string, dict logger.Event) logger.Event {
+	sub := &event{entry: Entry{Fields: map[string]any{}}}
+	logger.Replay(dict, sub)
+	return e.add(k, sub.entry.Fields)
+}
+
+func (e *event) Object(k string, obj logger.ObjectMarshaler) logger.Event {
+	dict := logger.Dict()
+	if obj != nil {
+		obj.MarshalLogObject(dict)
+	}
+	return e.Dict(k, dict)
+}
+
+func (e *event) Err(err error) logger.Event {
+	if err != nil {
+		e.add("error", err)
+	}
+	return e
+}
+
+func (e *event) Msg(msg string) {
+	e.entry.Message = msg
+	e.l.record(e.entry)
+}
+
+var (
+	_ logger.Logger = (*Logger)(nil)
+	_ logger.Event  = (*event)(nil)
+)
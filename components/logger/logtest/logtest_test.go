@@ -0,0 +1,64 @@
+package logtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger"
+)
+
+func TestLogger(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	log := New()
+	jobLog := log.With("job_id", "export")
+	ctx := logger.WithFields(context.Background(), "request_id", "req-1")
+
+	jobLog.Ctx(ctx).Error().
+		Int("attempts", 2).
+		Dur("duration", time.Second).
+		Err(fmt.Errorf("loading report: %w", errNotFound)).
+		Msg("Job failed")
+	log.Info().Msg("Done")
+
+	require.Len(t, log.Entries(), 2)
+	require.True(t, log.HasEntry(LevelError, "Job failed"))
+	require.True(t, log.HasEntry(LevelError, "Job failed",
+		"job_id", "export",
+		"request_id", "req-1",
+		"attempts", 2,
+		"duration", time.Second,
+		"error", errNotFound,
+	))
+	require.True(t, log.HasEntry(LevelError, "Job failed", "error", Contains("loading report"), "duration", Present()))
+	require.False(t, log.HasEntry(LevelError, "Job failed", "attempts", int64(2)))
+	require.False(t, log.HasEntry(LevelWarn, "Job failed"))
+	require.False(t, log.HasEntry(LevelInfo, "Done", "job_id", Present()))
+
+	require.Equal(t, "info Done", log.Entries()[1].String())
+
+	log.Reset()
+	require.Empty(t, log.Entries())
+}
+
+func TestLoggerConcurrent(t *testing.T) {
+	log := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.With("worker", i).Info().Msg("Working")
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, log.Find(LevelInfo, "Working"), 10)
+	require.True(t, log.HasEntry(LevelInfo, "Working", "worker", 7))
+}
@@ -1,33 +1,61 @@
 package logger
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 type (
 	Noop      struct{} // zero-size, safe to copy
-	noopEvent struct{} // zero-size, safe to reuse
+	noopEvent struct {
+		fatal bool // exits on Msg, for Fatal events of disabled loggers
+	}
 )
 
 // With returns itself
 func (Noop) With(_ ...any) Logger { return Noop{} }
 
+// Ctx returns itself
+func (Noop) Ctx(_ context.Context) Logger { return Noop{} }
+
+// Enabled is always false
+func (Noop) Enabled(Level) bool { return false }
+func (Noop) SetLevel(Level)     {}
+func (Noop) Level() Level       { return LevelDisabled }
+
+func (Noop) Trace() Event { return noopEvent{} }
 func (Noop) Debug() Event { return noopEvent{} }
 func (Noop) Info() Event  { return noopEvent{} }
 func (Noop) Warn() Event  { return noopEvent{} }
 func (Noop) Error() Event { return noopEvent{} }
 
+// Fatal logs nothing but still exits on Msg.
+func (Noop) Fatal() Event { return noopEvent{fatal: true} }
+
 // All builder methods are no-ops returning the same event.
-func (noopEvent) Str(string, string) Event        { return noopEvent{} }
-func (noopEvent) Int(string, int) Event           { return noopEvent{} }
-func (noopEvent) Int64(string, int64) Event       { return noopEvent{} }
-func (noopEvent) Uint64(string, uint64) Event     { return noopEvent{} }
-func (noopEvent) Float64(string, float64) Event   { return noopEvent{} }
-func (noopEvent) Bool(string, bool) Event         { return noopEvent{} }
-func (noopEvent) Time(string, time.Time) Event    { return noopEvent{} }
-func (noopEvent) Dur(string, time.Duration) Event { return noopEvent{} }
-func (noopEvent) Err(error) Event                 { return noopEvent{} }
-func (noopEvent) Bytes(string, []byte) Event      { return noopEvent{} }
-func (noopEvent) Any(string, any) Event           { return noopEvent{} }
-func (noopEvent) Msg(string)                      { /* no-op */ }
+func (e noopEvent) Str(string, string) Event             { return e }
+func (e noopEvent) Strs(string, []string) Event          { return e }
+func (e noopEvent) Int(string, int) Event                { return e }
+func (e noopEvent) Ints(string, []int) Event             { return e }
+func (e noopEvent) Int64(string, int64) Event            { return e }
+func (e noopEvent) Uint64(string, uint64) Event          { return e }
+func (e noopEvent) Float64(string, float64) Event        { return e }
+func (e noopEvent) Bool(string, bool) Event              { return e }
+func (e noopEvent) Time(string, time.Time) Event         { return e }
+func (e noopEvent) Dur(string, time.Duration) Event      { return e }
+func (e noopEvent) Err(error) Event                      { return e }
+func (e noopEvent) Bytes(string, []byte) Event           { return e }
+func (e noopEvent) Stringer(string, fmt.Stringer) Event  { return e }
+func (e noopEvent) Any(string, any) Event                { return e }
+func (e noopEvent) Dict(string, Event) Event             { return e }
+func (e noopEvent) Object(string, ObjectMarshaler) Event { return e }
+
+func (e noopEvent) Msg(string) {
+	if e.fatal {
+		exit(1)
+	}
+}
 
 var (
 	_ Logger = Noop{}
@@ -0,0 +1,484 @@
+package logger
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Redacted replaces the values of redacted fields.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are the key patterns of DefaultRedactor.
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"accesskey",
+	"privatekey",
+	"authorization",
+	"cookie",
+	"credential",
+	"cardnumber",
+	"cvv",
+}
+
+// DefaultRedactSegments are the key patterns of DefaultRedactor matched as whole segments
+// of the key only, being short enough to be part of unrelated words, e.g. "ssn" in "business_name".
+var DefaultRedactSegments = []string{
+	"ssn",
+}
+
+// maxRedactDepth bounds how deep Any values are walked.
+const maxRedactDepth = 16
+
+// Redactor decides which fields are redacted. A key is redacted when it contains one of
+// the patterns, ignoring case and non alphanumeric characters, so "apikey" matches
+// "api_key", "API-Key" and "X-Api-Key", or when one of its segments, split on non
+// alphanumeric characters and camel case, is one of the segment patterns. Struct fields
+// tagged `redact:"true"` are always redacted when logged with Any.
+type Redactor struct {
+	patterns []string
+	segments []string
+}
+
+// NewRedactor creates a Redactor matching the given key patterns.
+func NewRedactor(patterns ...string) *Redactor {
+	r := &Redactor{}
+	for _, p := range patterns {
+		if p = normalizeKey(p); p != "" {
+			r.patterns = append(r.patterns, p)
+		}
+	}
+	return r
+}
+
+// MatchSegments adds patterns matched as whole segments of the keys, returning r.
+func (r *Redactor) MatchSegments(patterns ...string) *Redactor {
+	for _, p := range patterns {
+		if p = normalizeKey(p); p != "" {
+			r.segments = append(r.segments, p)
+		}
+	}
+	return r
+}
+
+// DefaultRedactor returns a Redactor matching DefaultRedactKeys and DefaultRedactSegments.
+func DefaultRedactor() *Redactor {
+	return NewRedactor(DefaultRedactKeys...).MatchSegments(DefaultRedactSegments...)
+}
+
+// Key reports whether the values of key are redacted.
+func (r *Redactor) Key(key string) bool {
+	normalized := normalizeKey(key)
+	for _, p := range r.patterns {
+		if strings.Contains(normalized, p) {
+			return true
+		}
+	}
+
+	if len(r.segments) > 0 {
+		for _, segment := range keySegments(key) {
+			if slices.Contains(r.segments, segment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Value returns v with its redacted fields replaced, v itself if it has none.
+// Structs and maps with redacted fields are converted to maps keyed like their JSON encoding.
+func (r *Redactor) Value(v any) any {
+	if v == nil {
+		return nil
+	}
+	if out, changed := r.value(reflect.ValueOf(v), 0); changed {
+		return out
+	}
+	return v
+}
+
+// KV returns a copy of the kv pairs with the redacted values replaced.
+func (r *Redactor) KV(kv []any) []any {
+	out := make([]any, len(kv))
+	copy(out, kv)
+	for i := 0; i+1 < len(out); i += 2 {
+		if k, ok := out[i].(string); ok && r.Key(k) {
+			out[i+1] = Redacted
+		} else {
+			out[i+1] = r.Value(out[i+1])
+		}
+	}
+	return out
+}
+
+var (
+	jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+	textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func (r *Redactor) value(rv reflect.Value, depth int) (any, bool) {
+	if !rv.IsValid() {
+		return nil, false
+	}
+	if depth > maxRedactDepth || !rv.CanInterface() {
+		return nil, false
+	}
+
+	// values with their own encoding, e.g. time.Time, are kept as they are
+	if rv.Type().Implements(jsonMarshaler) || rv.Type().Implements(textMarshaler) {
+		return rv.Interface(), false
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return rv.Interface(), false
+		}
+		out, changed := r.value(rv.Elem(), depth+1)
+		if !changed {
+			return rv.Interface(), false
+		}
+		return out, true
+
+	case reflect.Struct:
+		fields := make(map[string]any, rv.NumField())
+		changed := r.structFields(rv, fields, depth)
+		if !changed {
+			return rv.Interface(), false
+		}
+		return fields, true
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface(), false
+		}
+		out := make(map[string]any, rv.Len())
+		changed := false
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			if r.Key(k) {
+				out[k] = Redacted
+				changed = true
+				continue
+			}
+			v, c := r.value(iter.Value(), depth+1)
+			if !c {
+				v = iter.Value().Interface()
+			}
+			out[k] = v
+			changed = changed || c
+		}
+		if !changed {
+			return rv.Interface(), false
+		}
+		return out, true
+
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface(), false
+		}
+		out := make([]any, rv.Len())
+		changed := false
+		for i := range out {
+			v, c := r.value(rv.Index(i), depth+1)
+			if !c {
+				v = rv.Index(i).Interface()
+			}
+			out[i] = v
+			changed = changed || c
+		}
+		if !changed {
+			return rv.Interface(), false
+		}
+		return out, true
+	}
+
+	return rv.Interface(), false
+}
+
+// structFields adds the exported fields of rv to fields, named like encoding/json names them.
+func (r *Redactor) structFields(rv reflect.Value, fields map[string]any, depth int) bool {
+	changed := false
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if !fv.CanInterface() {
+			continue
+		}
+
+		// fields of embedded structs are promoted, as encoding/json does
+		if f.Anonymous && name == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				changed = r.structFields(fv, fields, depth+1) || changed
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		if f.Tag.Get("redact") == "true" || r.Key(name) {
+			fields[name] = Redacted
+			changed = true
+			continue
+		}
+
+		v, c := r.value(fv, depth+1)
+		if !c {
+			v = fv.Interface()
+		}
+		fields[name] = v
+		changed = changed || c
+	}
+	return changed
+}
+
+// keySegments splits key on non alphanumeric characters and camel case, lowering the
+// segments: "customerSSN_last4" is "customer", "ssn", "last4".
+func keySegments(key string) []string {
+	var segments []string
+	runes := []rune(key)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				segments = append(segments, strings.ToLower(string(runes[start:i])))
+				start = -1
+			}
+			continue
+		}
+
+		// a segment starts at an upper case letter following a lower case one, or
+		// preceding one, as "Token" in "APIToken"
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				segments = append(segments, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		segments = append(segments, strings.ToLower(string(runes[start:])))
+	}
+	return segments
+}
+
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, key)
+}
+
+// WithRedaction returns a Logger that redacts the fields of l, including the ones
+// added by With and WithFields, before they reach its backend. A nil r is DefaultRedactor.
+//
+// NewZero and NewSlog already redact with DefaultRedactor; wrapping their loggers
+// replaces it with r, e.g. NewRedactor() only redacts the fields tagged `redact:"true"`.
+func WithRedaction(l Logger, r *Redactor) Logger {
+	if r == nil {
+		r = DefaultRedactor()
+	}
+	if rl, ok := l.(*redactLogger); ok {
+		l = rl.l
+	}
+	return &redactLogger{l: l, r: r}
+}
+
+type redactLogger struct {
+	l Logger
+	r *Redactor
+}
+
+func (rl *redactLogger) With(kv ...any) Logger {
+	return &redactLogger{l: rl.l.With(rl.r.KV(kv)...), r: rl.r}
+}
+
+func (rl *redactLogger) Ctx(ctx context.Context) Logger {
+	if kv := ContextFields(ctx); len(kv) > 0 {
+		ctx = context.WithValue(ctx, fieldsKey{}, rl.r.KV(kv))
+	}
+	return &redactLogger{l: rl.l.Ctx(ctx), r: rl.r}
+}
+
+func (rl *redactLogger) Enabled(level Level) bool { return rl.l.Enabled(level) }
+func (rl *redactLogger) SetLevel(level Level)     { rl.l.SetLevel(level) }
+func (rl *redactLogger) Level() Level             { return rl.l.Level() }
+
+func (rl *redactLogger) Trace() Event { return rl.event(rl.l.Trace()) }
+func (rl *redactLogger) Debug() Event { return rl.event(rl.l.Debug()) }
+func (rl *redactLogger) Info() Event  { return rl.event(rl.l.Info()) }
+func (rl *redactLogger) Warn() Event  { return rl.event(rl.l.Warn()) }
+func (rl *redactLogger) Error() Event { return rl.event(rl.l.Error()) }
+func (rl *redactLogger) Fatal() Event { return rl.event(rl.l.Fatal()) }
+
+func (rl *redactLogger) event(e Event) Event {
+	if _, ok := e.(noopEvent); ok {
+		return e
+	}
+	return &redactEvent{e: e, r: rl.r}
+}
+
+type redactEvent struct {
+	e Event
+	r *Redactor
+}
+
+// redact reports whether key is redacted, adding the redacted field if so.
+func (re *redactEvent) redact(key string) bool {
+	if !re.r.Key(key) {
+		return false
+	}
+	re.e = re.e.Str(key, Redacted)
+	return true
+}
+
+func (re *redactEvent) Str(k, v string) Event {
+	if !re.redact(k) {
+		re.e = re.e.Str(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Int(k string, v int) Event {
+	if !re.redact(k) {
+		re.e = re.e.Int(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Int64(k string, v int64) Event {
+	if !re.redact(k) {
+		re.e = re.e.Int64(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Uint64(k string, v uint64) Event {
+	if !re.redact(k) {
+		re.e = re.e.Uint64(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Float64(k string, v float64) Event {
+	if !re.redact(k) {
+		re.e = re.e.Float64(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Bool(k string, v bool) Event {
+	if !re.redact(k) {
+		re.e = re.e.Bool(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Time(k string, t time.Time) Event {
+	if !re.redact(k) {
+		re.e = re.e.Time(k, t)
+	}
+	return re
+}
+
+func (re *redactEvent) Dur(k string, d time.Duration) Event {
+	if !re.redact(k) {
+		re.e = re.e.Dur(k, d)
+	}
+	return re
+}
+
+func (re *redactEvent) Bytes(k string, b []byte) Event {
+	if !re.redact(k) {
+		re.e = re.e.Bytes(k, b)
+	}
+	return re
+}
+
+func (re *redactEvent) Strs(k string, v []string) Event {
+	if !re.redact(k) {
+		re.e = re.e.Strs(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Ints(k string, v []int) Event {
+	if !re.redact(k) {
+		re.e = re.e.Ints(k, v)
+	}
+	return re
+}
+
+func (re *redactEvent) Stringer(k string, v fmt.Stringer) Event {
+	if !re.redact(k) {
+		re.e = re.e.Stringer(k, v)
+	}
+	return re
+}
+
+// Dict redacts the nested fields too.
+func (re *redactEvent) Dict(k string, dict Event) Event {
+	if !re.redact(k) {
+		sub := &redactEvent{e: Dict(), r: re.r}
+		asDict(dict).replay(sub)
+		re.e = re.e.Dict(k, sub.e)
+	}
+	return re
+}
+
+func (re *redactEvent) Object(k string, obj ObjectMarshaler) Event {
+	return re.Dict(k, objectDict(obj))
+}
+
+func (re *redactEvent) Any(k string, v any) Event {
+	if !re.redact(k) {
+		re.e = re.e.Any(k, re.r.Value(v))
+	}
+	return re
+}
+
+func (re *redactEvent) Err(err error) Event { re.e = re.e.Err(err); return re }
+func (re *redactEvent) Msg(msg string)      { re.e.Msg(msg) }
+
+var (
+	_ Logger = (*redactLogger)(nil)
+	_ Event  = (*redactEvent)(nil)
+)
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type signupRequest struct {
+	Email    string    `json:"email"`
+	Password string    `json:"password"`
+	Phone    string    `json:"phone" redact:"true"`
+	Internal string    `json:"-"`
+	Profile  *profile  `json:"profile"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+type profile struct {
+	Name     string
+	APIToken string
+}
+
+func TestRedactorKey(t *testing.T) {
+	r := DefaultRedactor()
+
+	for _, key := range []string{"password", "API-Key", "api_key", "X-Api-Key", "AccessKey", "refresh_token", "Authorization",
+		"ssn", "SSN", "user_ssn", "customerSSN", "ssnLast4"} {
+		require.True(t, r.Key(key), key)
+	}
+	for _, key := range []string{"email", "id", "request_ip", "body_type",
+		"business_name", "address_number", "class_name", "access_note", "className", "BusinessName"} {
+		require.False(t, r.Key(key), key)
+	}
+}
+
+func TestKeySegments(t *testing.T) {
+	tests := []struct {
+		key      string
+		segments []string
+	}{
+		{"ssn", []string{"ssn"}},
+		{"business_name", []string{"business", "name"}},
+		{"customerSSN", []string{"customer", "ssn"}},
+		{"APIToken", []string{"api", "token"}},
+		{"X-Api-Key", []string{"x", "api", "key"}},
+		{"ssnLast4", []string{"ssn", "last4"}},
+		{"__", nil},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.segments, keySegments(tc.key), tc.key)
+	}
+}
+
+func TestRedactorValue(t *testing.T) {
+	r := DefaultRedactor()
+	sentAt := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	req := &signupRequest{
+		Email:    "ana@example.com",
+		Password: "hunter2",
+		Phone:    "5555555555",
+		Internal: "internal",
+		Profile:  &profile{Name: "Ana", APIToken: "tok"},
+		SentAt:   sentAt,
+	}
+
+	require.Equal(t, map[string]any{
+		"email":    "ana@example.com",
+		"password": Redacted,
+		"phone":    Redacted,
+		"profile":  map[string]any{"Name": "Ana", "APIToken": Redacted},
+		"sent_at":  sentAt,
+	}, r.Value(req))
+
+	require.Equal(t, []any{map[string]any{"user": "ana", "secret": Redacted}},
+		r.Value([]map[string]string{{"user": "ana", "secret": "s3cr3t"}}))
+
+	// values without redacted fields are returned as they are
+	clean := profile{Name: "Ana"}
+	require.Equal(t, clean, NewRedactor("password").Value(clean))
+}
+
+func TestWithRedaction(t *testing.T) {
+	var zeroBuf, slogBuf bytes.Buffer
+	loggers := map[string]Logger{
+		"zerolog": NewZero(zerolog.New(&zeroBuf)),
+		"slog":    NewSlog(slog.New(slog.NewJSONHandler(&slogBuf, nil))),
+		"noop":    Noop{},
+	}
+	buffers := map[string]*bytes.Buffer{"zerolog": &zeroBuf, "slog": &slogBuf}
+
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			ctx := WithFields(context.Background(), "session_token", "abc")
+
+			// redacted without WithRedaction
+			l.With("api_key", "key-1").
+				Ctx(ctx).
+				Info().
+				Str("AccessKey", "AKIA").
+				Int("cvv", 123).
+				Any("request_json", signupRequest{Email: "ana@example.com", Password: "hunter2"}).
+				Msg("hello")
+
+			if buffers[name] == nil {
+				return
+			}
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buffers[name].Bytes(), &entry))
+			require.Equal(t, Redacted, entry["api_key"])
+			require.Equal(t, Redacted, entry["session_token"])
+			require.Equal(t, Redacted, entry["AccessKey"])
+			require.Equal(t, Redacted, entry["cvv"])
+
+			body := entry["request_json"].(map[string]any)
+			require.Equal(t, "ana@example.com", body["email"])
+			require.Equal(t, Redacted, body["password"])
+
+			// WithRedaction replaces the default Redactor
+			buffers[name].Reset()
+			WithRedaction(l, NewRedactor("email")).Info().
+				Str("email", "ana@example.com").
+				Str("password", "hunter2").
+				Msg("hello")
+
+			entry = nil
+			require.NoError(t, json.Unmarshal(buffers[name].Bytes(), &entry))
+			require.Equal(t, Redacted, entry["email"])
+			require.Equal(t, "hunter2", entry["password"])
+		})
+	}
+}
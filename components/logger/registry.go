@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vanclief/ez"
+)
+
+// Registry names loggers so their level can be changed at runtime, see loggerhttp.
+type Registry struct {
+	mu      sync.RWMutex
+	loggers map[string]Logger
+}
+
+// DefaultRegistry is the Registry used by Register.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{loggers: make(map[string]Logger)}
+}
+
+// Register adds l to the DefaultRegistry under name and returns it.
+func Register(name string, l Logger) Logger {
+	return DefaultRegistry.Register(name, l)
+}
+
+// Register adds l under name, replacing any logger with the same name, and returns it.
+// Level changes apply to l and every logger derived from it.
+func (r *Registry) Register(name string, l Logger) Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loggers[name] = l
+	return l
+}
+
+// Names returns the registered names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Levels returns the level of every registered logger.
+func (r *Registry) Levels() map[string]Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	levels := make(map[string]Level, len(r.loggers))
+	for name, l := range r.loggers {
+		levels[name] = l.Level()
+	}
+	return levels
+}
+
+// Level returns the level of the logger registered under name.
+func (r *Registry) Level(name string) (Level, error) {
+	l, err := r.get(name)
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	return l.Level(), nil
+}
+
+// SetLevel sets the level of the logger registered under name.
+func (r *Registry) SetLevel(name string, level Level) error {
+	l, err := r.get(name)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	l.SetLevel(level)
+	return nil
+}
+
+func (r *Registry) get(name string) (Logger, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	l, ok := r.loggers[name]
+	if !ok {
+		msg := fmt.Sprintf("Logger %s is not registered", name)
+		return nil, ez.New(ez.ENOTFOUND, msg, nil)
+	}
+
+	return l, nil
+}
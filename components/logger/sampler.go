@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSamplePeriod    = time.Second
+	DefaultSummaryInterval = time.Minute
+)
+
+// SamplingConfig configures a Sampler. Events are counted per key, by default their
+// level and message, and the counters reset every Period.
+type SamplingConfig struct {
+	// First events of a key per period are always logged.
+	First int
+	// Thereafter every Thereafter-th event of a key is logged; 0 => none past First.
+	// With First and Thereafter both 0 every event is logged, up to Burst.
+	Thereafter int
+	// Burst caps the events of a key logged per period, whatever First and Thereafter allow; 0 => no cap.
+	Burst int
+	// Period after which the counters reset. Defaults to DefaultSamplePeriod.
+	Period time.Duration
+	// SummaryInterval is how often a warning with the number of suppressed events is logged.
+	// Defaults to DefaultSummaryInterval, < 0 => no summary.
+	SummaryInterval time.Duration
+	// Key groups events, e.g. to ignore the level. Defaults to the level and message.
+	Key func(level Level, msg string) string
+}
+
+// Sampler is a Logger dropping repeated events of the wrapped Logger. Fatal events are
+// never dropped. Loggers derived with With and Ctx share its counters.
+//
+// While events are suppressed a timer logs the summary every SummaryInterval, without
+// waiting for more traffic. Call Close on shutdown to stop it and log the last summary.
+type Sampler struct {
+	l     Logger
+	state *samplerState
+}
+
+type samplerState struct {
+	mu          sync.Mutex
+	cfg         SamplingConfig
+	now         func() time.Time
+	periodEnd   time.Time
+	counts      map[string]*sampleCount // reset every period
+	suppressed  map[string]int          // since the last summary
+	nextSummary time.Time
+	timer       *time.Timer // armed while events are suppressed
+	closed      bool
+	summary     Logger // the wrapped Logger, without the fields of derived loggers
+}
+
+type sampleCount struct {
+	seen   int
+	logged int
+}
+
+// NewSampler wraps l.
+func NewSampler(l Logger, cfg SamplingConfig) *Sampler {
+	if cfg.Period <= 0 {
+		cfg.Period = DefaultSamplePeriod
+	}
+	if cfg.SummaryInterval == 0 {
+		cfg.SummaryInterval = DefaultSummaryInterval
+	}
+	if cfg.Key == nil {
+		cfg.Key = func(level Level, msg string) string { return level.String() + " " + msg }
+	}
+
+	return &Sampler{
+		l: l,
+		state: &samplerState{
+			cfg:        cfg,
+			now:        time.Now,
+			counts:     make(map[string]*sampleCount),
+			suppressed: make(map[string]int),
+			summary:    l,
+		},
+	}
+}
+
+func (s *Sampler) With(kv ...any) Logger {
+	return &Sampler{l: s.l.With(kv...), state: s.state}
+}
+
+func (s *Sampler) Ctx(ctx context.Context) Logger {
+	return &Sampler{l: s.l.Ctx(ctx), state: s.state}
+}
+
+func (s *Sampler) Enabled(level Level) bool { return s.l.Enabled(level) }
+func (s *Sampler) SetLevel(level Level)     { s.l.SetLevel(level) }
+func (s *Sampler) Level() Level             { return s.l.Level() }
+
+func (s *Sampler) Trace() Event { return s.event(LevelTrace, s.l.Trace()) }
+func (s *Sampler) Debug() Event { return s.event(LevelDebug, s.l.Debug()) }
+func (s *Sampler) Info() Event  { return s.event(LevelInfo, s.l.Info()) }
+func (s *Sampler) Warn() Event  { return s.event(LevelWarn, s.l.Warn()) }
+func (s *Sampler) Error() Event { return s.event(LevelError, s.l.Error()) }
+func (s *Sampler) Fatal() Event { return s.l.Fatal() }
+
+func (s *Sampler) event(level Level, e Event) Event {
+	if _, ok := e.(noopEvent); ok {
+		return e
+	}
+	return &sampledEvent{e: e, state: s.state, level: level}
+}
+
+// Flush logs the summary of the events suppressed since the last one, if any.
+func (s *Sampler) Flush() {
+	s.state.mu.Lock()
+	suppressed := s.state.takeSuppressed()
+	s.state.mu.Unlock()
+
+	s.state.logSummary(suppressed)
+}
+
+// Close stops the summary timer and logs the summary of the events suppressed since
+// the last one, if any. Events logged after Close are still sampled, call Flush for
+// their summary.
+func (s *Sampler) Close() {
+	s.state.mu.Lock()
+	s.state.closed = true
+	if s.state.timer != nil {
+		s.state.timer.Stop()
+		s.state.timer = nil
+	}
+	suppressed := s.state.takeSuppressed()
+	s.state.mu.Unlock()
+
+	s.state.logSummary(suppressed)
+}
+
+// allow counts an event and reports whether it's logged. It also returns the
+// suppressed counts when a summary is due.
+func (st *samplerState) allow(level Level, msg string) (bool, map[string]int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	if !now.Before(st.periodEnd) {
+		clear(st.counts)
+		st.periodEnd = now.Add(st.cfg.Period)
+	}
+	if st.nextSummary.IsZero() {
+		st.nextSummary = now.Add(st.cfg.SummaryInterval)
+	}
+
+	key := st.cfg.Key(level, msg)
+	c := st.counts[key]
+	if c == nil {
+		c = &sampleCount{}
+		st.counts[key] = c
+	}
+	c.seen++
+
+	ok := st.sampled(c.seen)
+	if ok && st.cfg.Burst > 0 && c.logged >= st.cfg.Burst {
+		ok = false
+	}
+
+	if ok {
+		c.logged++
+	} else {
+		st.suppressed[key]++
+		st.armTimer(now)
+	}
+
+	var summary map[string]int
+	if st.cfg.SummaryInterval > 0 && !now.Before(st.nextSummary) {
+		summary = st.takeSuppressed()
+		st.nextSummary = now.Add(st.cfg.SummaryInterval)
+	}
+
+	return ok, summary
+}
+
+// armTimer schedules the summary of suppressed events at nextSummary, unless it already is.
+func (st *samplerState) armTimer(now time.Time) {
+	if st.cfg.SummaryInterval <= 0 || st.timer != nil || st.closed {
+		return
+	}
+	st.timer = time.AfterFunc(max(st.nextSummary.Sub(now), 0), st.summarize)
+}
+
+// summarize logs the summary when it's due. The timer is armed again by the next
+// suppressed event, so it doesn't run while nothing is suppressed.
+func (st *samplerState) summarize() {
+	st.mu.Lock()
+	st.timer = nil
+	var summary map[string]int
+	if now := st.now(); !st.closed && !now.Before(st.nextSummary) {
+		summary = st.takeSuppressed()
+		st.nextSummary = now.Add(st.cfg.SummaryInterval)
+	} else if len(st.suppressed) > 0 {
+		st.armTimer(now)
+	}
+	st.mu.Unlock()
+
+	st.logSummary(summary)
+}
+
+// sampled reports whether the n-th event of a key in a period passes First and Thereafter.
+func (st *samplerState) sampled(n int) bool {
+	first, thereafter := st.cfg.First, st.cfg.Thereafter
+	switch {
+	case first == 0 && thereafter == 0, n <= first:
+		return true
+	case thereafter == 0:
+		return false
+	}
+	return (n-first)%thereafter == 0
+}
+
+func (st *samplerState) takeSuppressed() map[string]int {
+	if len(st.suppressed) == 0 {
+		return nil
+	}
+	suppressed := st.suppressed
+	st.suppressed = make(map[string]int)
+	return suppressed
+}
+
+func (st *samplerState) logSummary(suppressed map[string]int) {
+	if len(suppressed) == 0 {
+		return
+	}
+
+	total := 0
+	for _, n := range suppressed {
+		total += n
+	}
+
+	st.summary.Warn().
+		Int("suppressed", total).
+		Any("messages", suppressed).
+		Msg("Log messages suppressed")
+}
+
+type sampledEvent struct {
+	e     Event
+	state *samplerState
+	level Level
+}
+
+func (se *sampledEvent) Str(k, v string) Event               { se.e = se.e.Str(k, v); return se }
+func (se *sampledEvent) Int(k string, v int) Event           { se.e = se.e.Int(k, v); return se }
+func (se *sampledEvent) Int64(k string, v int64) Event       { se.e = se.e.Int64(k, v); return se }
+func (se *sampledEvent) Uint64(k string, v uint64) Event     { se.e = se.e.Uint64(k, v); return se }
+func (se *sampledEvent) Float64(k string, v float64) Event   { se.e = se.e.Float64(k, v); return se }
+func (se *sampledEvent) Bool(k string, v bool) Event         { se.e = se.e.Bool(k, v); return se }
+func (se *sampledEvent) Time(k string, t time.Time) Event    { se.e = se.e.Time(k, t); return se }
+func (se *sampledEvent) Dur(k string, d time.Duration) Event { se.e = se.e.Dur(k, d); return se }
+func (se *sampledEvent) Bytes(k string, b []byte) Event      { se.e = se.e.Bytes(k, b); return se }
+func (se *sampledEvent) Err(err error) Event                 { se.e = se.e.Err(err); return se }
+func (se *sampledEvent) Any(k string, v any) Event           { se.e = se.e.Any(k, v); return se }
+func (se *sampledEvent) Strs(k string, v []string) Event     { se.e = se.e.Strs(k, v); return se }
+func (se *sampledEvent) Ints(k string, v []int) Event        { se.e = se.e.Ints(k, v); return se }
+func (se *sampledEvent) Stringer(k string, v fmt.Stringer) Event {
+	se.e = se.e.Stringer(k, v)
+	return se
+}
+func (se *sampledEvent) Dict(k string, dict Event) Event { se.e = se.e.Dict(k, dict); return se }
+func (se *sampledEvent) Object(k string, obj ObjectMarshaler) Event {
+	se.e = se.e.Object(k, obj)
+	return se
+}
+
+func (se *sampledEvent) Msg(msg string) {
+	ok, summary := se.state.allow(se.level, msg)
+	if ok {
+		se.e.Msg(msg)
+	}
+	se.state.logSummary(summary)
+}
+
+var (
+	_ Logger = (*Sampler)(nil)
+	_ Event  = (*sampledEvent)(nil)
+)
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// jsonLines decodes the JSON lines written to buf since the last call.
+func jsonLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func newTestSampler(cfg SamplingConfig) (*Sampler, *bytes.Buffer, func(time.Duration)) {
+	var buf bytes.Buffer
+	s := NewSampler(NewSlog(slog.New(slog.NewJSONHandler(&buf, nil))), cfg)
+
+	now := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	s.state.now = func() time.Time { return now }
+	return s, &buf, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSampler(t *testing.T) {
+	s, buf, advance := newTestSampler(SamplingConfig{First: 2, Thereafter: 3, SummaryInterval: -1})
+
+	for i := 1; i <= 10; i++ {
+		s.With("job_id", "export").Warn().Int("n", i).Msg("Job already running, skipping")
+	}
+	s.Warn().Msg("Other message")
+
+	var logged []float64
+	for _, line := range jsonLines(t, buf) {
+		if line["msg"] == "Job already running, skipping" {
+			logged = append(logged, line["n"].(float64))
+		}
+	}
+	require.Equal(t, []float64{1, 2, 5, 8}, logged)
+
+	// the counters reset every period
+	advance(time.Second)
+	s.Warn().Int("n", 11).Msg("Job already running, skipping")
+	require.Len(t, jsonLines(t, buf), 1)
+}
+
+func TestSamplerBurstAndSummary(t *testing.T) {
+	s, buf, advance := newTestSampler(SamplingConfig{Burst: 3, Period: time.Minute})
+
+	for i := 0; i < 5; i++ {
+		s.Error().Msg("Could not connect to the database")
+	}
+	s.Info().Msg("Request done")
+	require.Len(t, jsonLines(t, buf), 4)
+
+	// the summary comes with the first event past the interval
+	advance(time.Minute)
+	s.Error().Msg("Could not connect to the database")
+
+	lines := jsonLines(t, buf)
+	require.Len(t, lines, 2)
+	require.Equal(t, "Log messages suppressed", lines[1]["msg"])
+	require.Equal(t, float64(2), lines[1]["suppressed"])
+	require.Equal(t, map[string]any{"error Could not connect to the database": float64(2)}, lines[1]["messages"])
+
+	// Flush logs what was suppressed since
+	for i := 0; i < 4; i++ {
+		s.Error().Msg("Could not connect to the database")
+	}
+	require.Len(t, jsonLines(t, buf), 2)
+
+	s.Flush()
+	lines = jsonLines(t, buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, float64(2), lines[0]["suppressed"])
+
+	s.Flush()
+	require.Empty(t, jsonLines(t, buf))
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the summary timer.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines(t *testing.T) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return jsonLines(t, &b.buf)
+}
+
+func TestSamplerSummaryTimer(t *testing.T) {
+	var buf syncBuffer
+	s := NewSampler(NewSlog(slog.New(slog.NewJSONHandler(&buf, nil))), SamplingConfig{
+		Burst:           1,
+		Period:          time.Minute,
+		SummaryInterval: 20 * time.Millisecond,
+	})
+	defer s.Close()
+
+	for i := 0; i < 3; i++ {
+		s.Error().Msg("Could not connect to the database")
+	}
+	require.Len(t, buf.lines(t), 1)
+
+	// no other event is logged, the summary still comes
+	var lines []map[string]any
+	require.Eventually(t, func() bool {
+		lines = append(lines, buf.lines(t)...)
+		return len(lines) > 0
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, "Log messages suppressed", lines[0]["msg"])
+	require.Equal(t, float64(2), lines[0]["suppressed"])
+
+	// the timer is only armed again by suppressed events
+	s.state.mu.Lock()
+	require.Nil(t, s.state.timer)
+	s.state.mu.Unlock()
+
+	// Close logs the pending summary and stops the timer
+	s.Error().Msg("Could not connect to the database")
+	s.Close()
+	lines = buf.lines(t)
+	require.Len(t, lines, 1)
+	require.Equal(t, float64(1), lines[0]["suppressed"])
+
+	s.Error().Msg("Could not connect to the database")
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, buf.lines(t))
+}
@@ -3,34 +3,103 @@ package logger
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"log/slog"
+	"runtime"
 	"time"
 )
 
+// slog has no trace or fatal levels, they are logged below debug and above error.
+const (
+	slogLevelTrace = slog.LevelDebug - 4
+	slogLevelFatal = slog.LevelError + 4
+)
+
 type slogLogger struct {
-	l *slog.Logger
+	l     *slog.Logger
+	ctx   context.Context // passed on to the handler
+	level *LevelVar
 }
 
-func NewSlog(l *slog.Logger) Logger { return &slogLogger{l: l} }
+// NewSlog wraps l. Its minimum level starts at the lowest level the handler of l is
+// enabled for, and is from then on the only one filtering events: records are passed
+// to the handler without asking it, so SetLevel can go below its level.
+// Fields are redacted with DefaultRedactor, see WithRedaction to change it.
+func NewSlog(l *slog.Logger) Logger {
+	ctx := context.Background()
+	level := LevelDisabled
+	for lv := LevelTrace; lv < LevelDisabled; lv++ {
+		if l.Enabled(ctx, slogLevel(lv)) {
+			level = lv
+			break
+		}
+	}
+	return WithRedaction(&slogLogger{l: l, ctx: ctx, level: NewLevelVar(level)}, nil)
+}
 
 func (s *slogLogger) With(kv ...any) Logger {
-	return &slogLogger{l: s.l.With(kv...)}
+	return &slogLogger{l: s.l.With(kv...), ctx: s.ctx, level: s.level}
 }
 
-func (s *slogLogger) Debug() Event { return &slogEvent{l: s.l, level: slog.LevelDebug} }
-func (s *slogLogger) Info() Event  { return &slogEvent{l: s.l, level: slog.LevelInfo} }
-func (s *slogLogger) Warn() Event  { return &slogEvent{l: s.l, level: slog.LevelWarn} }
-func (s *slogLogger) Error() Event { return &slogEvent{l: s.l, level: slog.LevelError} }
+// Ctx also passes ctx to the slog handler, so handlers reading values from it keep working.
+func (s *slogLogger) Ctx(ctx context.Context) Logger {
+	if ctx == nil {
+		return s
+	}
+	l := s.l
+	if kv := ContextFields(ctx); len(kv) > 0 {
+		l = l.With(kv...)
+	}
+	return &slogLogger{l: l, ctx: ctx, level: s.level}
+}
+
+func (s *slogLogger) Enabled(level Level) bool { return s.level.Enabled(level) }
+func (s *slogLogger) SetLevel(level Level)     { s.level.Set(level) }
+func (s *slogLogger) Level() Level             { return s.level.Level() }
+
+func (s *slogLogger) Trace() Event { return s.event(LevelTrace) }
+func (s *slogLogger) Debug() Event { return s.event(LevelDebug) }
+func (s *slogLogger) Info() Event  { return s.event(LevelInfo) }
+func (s *slogLogger) Warn() Event  { return s.event(LevelWarn) }
+func (s *slogLogger) Error() Event { return s.event(LevelError) }
+func (s *slogLogger) Fatal() Event { return s.event(LevelFatal) }
+
+func (s *slogLogger) event(level Level) Event {
+	if !s.Enabled(level) {
+		return noopEvent{fatal: level == LevelFatal}
+	}
+	return &slogEvent{l: s.l, ctx: s.ctx, level: slogLevel(level), fatal: level == LevelFatal}
+}
+
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case LevelTrace:
+		return slogLevelTrace
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	}
+	return slogLevelFatal
+}
 
 type slogEvent struct {
 	l     *slog.Logger
+	ctx   context.Context
 	level slog.Level
+	fatal bool
 	attrs []slog.Attr
 }
 
 func (e *slogEvent) add(a slog.Attr) *slogEvent          { e.attrs = append(e.attrs, a); return e }
 func (e *slogEvent) Str(k, v string) Event               { return e.add(slog.String(k, v)) }
+func (e *slogEvent) Strs(k string, v []string) Event     { return e.add(slog.Any(k, v)) }
 func (e *slogEvent) Int(k string, v int) Event           { return e.add(slog.Int(k, v)) }
+func (e *slogEvent) Ints(k string, v []int) Event        { return e.add(slog.Any(k, v)) }
 func (e *slogEvent) Int64(k string, v int64) Event       { return e.add(slog.Int64(k, v)) }
 func (e *slogEvent) Uint64(k string, v uint64) Event     { return e.add(slog.Uint64(k, v)) }
 func (e *slogEvent) Float64(k string, v float64) Event   { return e.add(slog.Float64(k, v)) }
@@ -45,14 +114,39 @@ func (e *slogEvent) Bytes(k string, b []byte) Event {
 	return e.add(slog.String(k, hexed))
 }
 
+func (e *slogEvent) Stringer(k string, v fmt.Stringer) Event {
+	return e.add(slog.String(k, stringOf(v)))
+}
+
 func (e *slogEvent) Err(err error) Event {
 	if err != nil {
 		e.attrs = append(e.attrs, slog.Any("error", err))
+		errFields(e, err)
 	}
 	return e
 }
-func (e *slogEvent) Any(k string, v any) Event { return e.add(slog.Any(k, v)) }
-func (e *slogEvent) Msg(msg string)            { e.l.LogAttrs(context.Background(), e.level, msg, e.attrs...) }
+
+func (e *slogEvent) Dict(k string, dict Event) Event {
+	sub := &slogEvent{}
+	asDict(dict).replay(sub)
+	return e.add(slog.Attr{Key: k, Value: slog.GroupValue(sub.attrs...)})
+}
+
+func (e *slogEvent) Object(k string, obj ObjectMarshaler) Event { return e.Dict(k, objectDict(obj)) }
+func (e *slogEvent) Any(k string, v any) Event                  { return e.add(slog.Any(k, v)) }
+
+func (e *slogEvent) Msg(msg string) {
+	// the logger checked the level, the handler is called directly as slog.Logger would
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:]) // skip Callers and Msg
+	r := slog.NewRecord(time.Now(), e.level, msg, pcs[0])
+	r.AddAttrs(e.attrs...)
+	_ = e.l.Handler().Handle(e.ctx, r)
+
+	if e.fatal {
+		exit(1)
+	}
+}
 
 var (
 	_ Logger = (*slogLogger)(nil)
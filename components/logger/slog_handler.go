@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogHandler returns a slog.Handler forwarding records to l, so libraries logging
+// through log/slog go through the same backend, redaction and levels as the app.
+// Groups are nested with Event.Dict and errors under "error" or "err" are logged with
+// Event.Err. The record time is left to the backend.
+//
+// Records above slog.LevelError are logged as errors, never as fatal events.
+func NewSlogHandler(l Logger) slog.Handler {
+	return &slogHandler{l: l, attrs: make([][]slog.Attr, 1)}
+}
+
+// SetSlogDefault makes l the backend of slog.Default and of the log package.
+func SetSlogDefault(l Logger) {
+	slog.SetDefault(slog.New(NewSlogHandler(l)))
+}
+
+type slogHandler struct {
+	l      Logger
+	groups []string
+	attrs  [][]slog.Attr // attrs[0] at the top level, attrs[i] in groups[:i]
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.Enabled(fromSlogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	l := h.l
+	if ctx != nil {
+		l = l.Ctx(ctx)
+	}
+
+	var e Event
+	switch fromSlogLevel(r.Level) {
+	case LevelTrace:
+		e = l.Trace()
+	case LevelDebug:
+		e = l.Debug()
+	case LevelInfo:
+		e = l.Info()
+	case LevelWarn:
+		e = l.Warn()
+	default:
+		e = l.Error()
+	}
+
+	// the record attrs go in the innermost group
+	inner := append([]slog.Attr(nil), h.attrs[len(h.groups)]...)
+	r.Attrs(func(a slog.Attr) bool {
+		inner = append(inner, a)
+		return true
+	})
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		dict := addAttrs(Dict(), inner)
+		inner = append([]slog.Attr(nil), h.attrs[i]...)
+		if len(asDict(dict).fields) > 0 {
+			inner = append(inner, slog.Any(h.groups[i], dictValue{dict}))
+		}
+	}
+
+	addAttrs(e, inner).Msg(r.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	clone := h.clone()
+	last := len(clone.groups)
+	clone.attrs[last] = append(clone.attrs[last][:len(clone.attrs[last]):len(clone.attrs[last])], attrs...)
+	return clone
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := h.clone()
+	clone.groups = append(clone.groups, name)
+	clone.attrs = append(clone.attrs, nil)
+	return clone
+}
+
+func (h *slogHandler) clone() *slogHandler {
+	return &slogHandler{
+		l:      h.l,
+		groups: h.groups[:len(h.groups):len(h.groups)],
+		attrs:  append([][]slog.Attr(nil), h.attrs...),
+	}
+}
+
+// dictValue carries a nested group built by Handle through a slog.Attr.
+type dictValue struct{ e Event }
+
+func addAttrs(e Event, attrs []slog.Attr) Event {
+	for _, a := range attrs {
+		e = addAttr(e, a)
+	}
+	return e
+}
+
+func addAttr(e Event, a slog.Attr) Event {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return e
+	}
+
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		return e.Str(a.Key, v.String())
+	case slog.KindInt64:
+		return e.Int64(a.Key, v.Int64())
+	case slog.KindUint64:
+		return e.Uint64(a.Key, v.Uint64())
+	case slog.KindFloat64:
+		return e.Float64(a.Key, v.Float64())
+	case slog.KindBool:
+		return e.Bool(a.Key, v.Bool())
+	case slog.KindDuration:
+		return e.Dur(a.Key, v.Duration())
+	case slog.KindTime:
+		return e.Time(a.Key, v.Time())
+	case slog.KindGroup:
+		// groups without a key are inlined, empty ones are dropped
+		if a.Key == "" {
+			return addAttrs(e, v.Group())
+		}
+		if len(v.Group()) == 0 {
+			return e
+		}
+		return e.Dict(a.Key, addAttrs(Dict(), v.Group()))
+	}
+
+	switch val := v.Any().(type) {
+	case dictValue:
+		return e.Dict(a.Key, val.e)
+	case error:
+		if a.Key == "error" || a.Key == "err" {
+			return e.Err(val)
+		}
+	}
+	return e.Any(a.Key, v.Any())
+}
+
+// fromSlogLevel maps a slog level to the Level whose range it falls in, as slogLevel maps them back.
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level <= slogLevelTrace:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	}
+	return LevelError
+}
+
+var _ slog.Handler = (*slogHandler)(nil)
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := WithRedaction(NewZero(zerolog.New(&buf)), nil)
+	l.SetLevel(LevelInfo)
+
+	s := slog.New(NewSlogHandler(l)).With("component", "cache")
+	require.False(t, s.Enabled(context.Background(), slog.LevelDebug))
+	require.True(t, s.Enabled(context.Background(), slog.LevelWarn))
+
+	s.Debug("dropped")
+
+	ctx := WithFields(context.Background(), "request_id", "req-1")
+	s.WithGroup("redis").
+		With("addr", "localhost:6379", "password", "hunter2").
+		WithGroup("empty").
+		WarnContext(ctx, "Cache miss",
+			"key", "user:1",
+			slog.Group("retry", "attempt", 2),
+			"err", errors.New("timeout"),
+		)
+
+	lines := jsonLines(t, &buf)
+	require.Len(t, lines, 1)
+	line := lines[0]
+
+	require.Equal(t, "warn", line["level"])
+	require.Equal(t, "Cache miss", line["message"])
+	require.Equal(t, "cache", line["component"])
+	require.Equal(t, "req-1", line["request_id"])
+	require.Equal(t, map[string]any{
+		"addr":     "localhost:6379",
+		"password": Redacted,
+		"empty": map[string]any{
+			"key":   "user:1",
+			"retry": map[string]any{"attempt": 2.0},
+			"error": "timeout",
+		},
+	}, line["redis"])
+
+	// records above slog.LevelError are errors, groups without attrs are dropped
+	s.WithGroup("unused").Log(context.Background(), slog.LevelError+8, "Connection lost")
+
+	lines = jsonLines(t, &buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, "error", lines[0]["level"])
+	require.NotContains(t, lines[0], "unused")
+}
@@ -1,16 +1,36 @@
 package logger
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
-type zeroLogger struct{ l zerolog.Logger }
+type zeroLogger struct {
+	l      zerolog.Logger
+	level  *LevelVar
+	global bool // l follows the level of log.Logger, see globalZero
+}
+
+// NewZero wraps l. Its minimum level starts at the level of l and SetLevel can then go
+// below it, but never below zerolog's global level, checked on every event.
+// Fields are redacted with DefaultRedactor, see WithRedaction to change it.
+func NewZero(l zerolog.Logger) Logger {
+	level := max(l.GetLevel(), zerolog.GlobalLevel())
+	zl := &zeroLogger{l: l.Level(zerolog.TraceLevel), level: NewLevelVar(fromZeroLevel(level))}
+	return WithRedaction(zl, nil)
+}
 
-func NewZero(l zerolog.Logger) Logger { return &zeroLogger{l: l} }
+func fromZeroLevel(level zerolog.Level) Level {
+	if level > zerolog.FatalLevel {
+		return LevelDisabled
+	}
+	return Level(level)
+}
 
 func (z *zeroLogger) With(kv ...any) Logger {
 	ctx := z.l.With()
@@ -46,18 +66,58 @@ func (z *zeroLogger) With(kv ...any) Logger {
 			ctx = ctx.Interface(k, v)
 		}
 	}
-	return &zeroLogger{l: ctx.Logger()}
+	return &zeroLogger{l: ctx.Logger(), level: z.level, global: z.global}
+}
+
+func (z *zeroLogger) Ctx(ctx context.Context) Logger {
+	kv := ContextFields(ctx)
+	if len(kv) == 0 {
+		return z
+	}
+	return z.With(kv...)
 }
 
-func (z *zeroLogger) Debug() Event { return &zeroEvent{e: z.l.Debug()} }
-func (z *zeroLogger) Info() Event  { return &zeroEvent{e: z.l.Info()} }
-func (z *zeroLogger) Warn() Event  { return &zeroEvent{e: z.l.Warn()} }
-func (z *zeroLogger) Error() Event { return &zeroEvent{e: z.l.Error()} }
+func (z *zeroLogger) Enabled(level Level) bool {
+	return z.level.Enabled(level) && zerolog.Level(level) >= z.zeroLevel()
+}
+
+func (z *zeroLogger) SetLevel(level Level) { z.level.Set(level) }
+func (z *zeroLogger) Level() Level         { return max(z.level.Level(), fromZeroLevel(z.zeroLevel())) }
+
+// zeroLevel returns the minimum level set on zerolog, read on every call so later
+// changes to zerolog.SetGlobalLevel and log.Logger apply.
+func (z *zeroLogger) zeroLevel() zerolog.Level {
+	if z.global {
+		return max(zerolog.GlobalLevel(), log.Logger.GetLevel())
+	}
+	return zerolog.GlobalLevel()
+}
+
+func (z *zeroLogger) Trace() Event { return z.event(LevelTrace) }
+func (z *zeroLogger) Debug() Event { return z.event(LevelDebug) }
+func (z *zeroLogger) Info() Event  { return z.event(LevelInfo) }
+func (z *zeroLogger) Warn() Event  { return z.event(LevelWarn) }
+func (z *zeroLogger) Error() Event { return z.event(LevelError) }
+func (z *zeroLogger) Fatal() Event { return z.event(LevelFatal) }
+
+func (z *zeroLogger) event(level Level) Event {
+	if !z.Enabled(level) {
+		return noopEvent{fatal: level == LevelFatal}
+	}
+
+	if level == LevelFatal {
+		// zerolog flushes its writer before exiting
+		return &zeroEvent{e: z.l.Fatal()}
+	}
+	return &zeroEvent{e: z.l.WithLevel(zerolog.Level(level))}
+}
 
 type zeroEvent struct{ e *zerolog.Event }
 
 func (e *zeroEvent) Str(k, v string) Event               { e.e = e.e.Str(k, v); return e }
+func (e *zeroEvent) Strs(k string, v []string) Event     { e.e = e.e.Strs(k, v); return e }
 func (e *zeroEvent) Int(k string, v int) Event           { e.e = e.e.Int(k, v); return e }
+func (e *zeroEvent) Ints(k string, v []int) Event        { e.e = e.e.Ints(k, v); return e }
 func (e *zeroEvent) Int64(k string, v int64) Event       { e.e = e.e.Int64(k, v); return e }
 func (e *zeroEvent) Uint64(k string, v uint64) Event     { e.e = e.e.Uint64(k, v); return e }
 func (e *zeroEvent) Float64(k string, v float64) Event   { e.e = e.e.Float64(k, v); return e }
@@ -79,16 +139,56 @@ func (e *zeroEvent) Bytes(k string, b []byte) Event {
 	return e
 }
 
+func (e *zeroEvent) Stringer(k string, v fmt.Stringer) Event {
+	e.e = e.e.Str(k, stringOf(v))
+	return e
+}
+
 func (e *zeroEvent) Err(err error) Event {
 	if err != nil {
 		e.e = e.e.Err(err)
+		errFields(e, err)
 	}
 	return e
 }
-func (e *zeroEvent) Any(k string, v any) Event { e.e = e.e.Interface(k, v); return e }
-func (e *zeroEvent) Msg(msg string)            { e.e.Msg(msg) }
+
+func (e *zeroEvent) Dict(k string, dict Event) Event {
+	sub := &zeroEvent{e: zerolog.Dict()}
+	asDict(dict).replay(sub)
+	e.e = e.e.Dict(k, sub.e)
+	return e
+}
+
+func (e *zeroEvent) Object(k string, obj ObjectMarshaler) Event { return e.Dict(k, objectDict(obj)) }
+func (e *zeroEvent) Any(k string, v any) Event                  { e.e = e.e.Interface(k, v); return e }
+func (e *zeroEvent) Msg(msg string)                             { e.e.Msg(msg) }
+
+// globalZero logs through the global zerolog logger, read on every call so it
+// follows the changes made by log.Logger = log.Output(...). Its minimum level is the
+// highest of zerolog's global level, the level of log.Logger and SetLevel's.
+type globalZero struct{}
+
+// globalLevel only raises the minimum level of globalZero above zerolog's.
+var globalLevel = NewLevelVar(LevelTrace)
+
+func (globalZero) logger() Logger {
+	return &zeroLogger{l: log.Logger, level: globalLevel, global: true}
+}
+
+func (g globalZero) With(kv ...any) Logger          { return g.logger().With(kv...) }
+func (g globalZero) Ctx(ctx context.Context) Logger { return g.logger().Ctx(ctx) }
+func (g globalZero) Enabled(level Level) bool       { return g.logger().Enabled(level) }
+func (g globalZero) SetLevel(level Level)           { globalLevel.Set(level) }
+func (g globalZero) Level() Level                   { return g.logger().Level() }
+func (g globalZero) Trace() Event                   { return g.logger().Trace() }
+func (g globalZero) Debug() Event                   { return g.logger().Debug() }
+func (g globalZero) Info() Event                    { return g.logger().Info() }
+func (g globalZero) Warn() Event                    { return g.logger().Warn() }
+func (g globalZero) Error() Event                   { return g.logger().Error() }
+func (g globalZero) Fatal() Event                   { return g.logger().Fatal() }
 
 var (
 	_ Logger = (*zeroLogger)(nil)
+	_ Logger = globalZero{}
 	_ Event  = (*zeroEvent)(nil)
 )
@@ -0,0 +1,68 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// State is the lifecycle state of a queued job.
+type State string
+
+const (
+	StatePending State = "pending" // waiting for its run time or a free worker
+	StateRunning State = "running" // claimed by a worker
+	StateDone    State = "done"    // handled successfully
+	StateDead    State = "dead"    // failed permanently or ran out of attempts
+)
+
+// Payload is the typed data of a job. Kind names the handler the job is dispatched to,
+// see Register. Payloads are stored as JSON.
+type Payload interface {
+	Kind() string
+}
+
+// Job is a row of the queue table.
+type Job struct {
+	bun.BaseModel `bun:"table:queue_jobs,alias:queue_job"`
+
+	ID          int64           `bun:"id,pk,autoincrement"`
+	Queue       string          `bun:"queue,notnull"`
+	Kind        string          `bun:"kind,notnull"`
+	Payload     json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	Priority    int             `bun:"priority,notnull"` // higher runs first
+	State       State           `bun:"state,notnull"`
+	Attempts    int             `bun:"attempts,notnull"`
+	MaxAttempts int             `bun:"max_attempts,notnull"`
+	RunAt       time.Time       `bun:"run_at,notnull"`
+	UniqueKey   string          `bun:"unique_key,nullzero"` // at most one pending or running job per key
+	LastError   string          `bun:"last_error,nullzero"`
+	LockedBy    string          `bun:"locked_by,nullzero"`
+	LockedAt    time.Time       `bun:"locked_at,nullzero"`
+	CreatedAt   time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+	FinishedAt  time.Time       `bun:"finished_at,nullzero"`
+}
+
+// permanentError marks a handler error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job goes straight to StateDead instead of being retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// isPermanent reports whether err was wrapped with Permanent.
+func isPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
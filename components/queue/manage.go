@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+// Get returns the job of the queue with the given id.
+func (q *Queue) Get(ctx context.Context, id int64) (*Job, error) {
+	job := new(Job)
+
+	err := q.db.NewSelect().
+		Model(job).
+		Where("id = ?", id).
+		Where("queue = ?", q.name).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, jobNotFound(id, err)
+		}
+		return nil, ez.Wrap(err)
+	}
+
+	return job, nil
+}
+
+// DeadJobs returns up to limit of the most recently dead jobs of the queue, newest first.
+func (q *Queue) DeadJobs(ctx context.Context, limit int) ([]Job, error) {
+	if limit <= 0 {
+		return nil, ez.New(ez.EINVALID, "limit must be positive", nil)
+	}
+
+	jobs := []Job{}
+	err := q.db.NewSelect().
+		Model(&jobs).
+		Where("queue = ?", q.name).
+		Where("state = ?", StateDead).
+		Order("finished_at DESC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	return jobs, nil
+}
+
+// Retry moves a dead job of the queue back to pending with a fresh set of attempts, to run right away.
+func (q *Queue) Retry(ctx context.Context, id int64) error {
+	res, err := q.db.NewUpdate().
+		Model((*Job)(nil)).
+		Set("state = ?", StatePending).
+		Set("attempts = 0").
+		Set("run_at = now()").
+		Set("finished_at = NULL").
+		Where("id = ?", id).
+		Where("queue = ?", q.name).
+		Where("state = ?", StateDead).
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return ez.Wrap(err)
+	}
+	if affected == 0 {
+		errMsg := fmt.Sprintf("dead job %d not found", id)
+		return ez.New(ez.ENOTFOUND, errMsg, nil)
+	}
+
+	return nil
+}
+
+// Prune deletes done and dead jobs of the queue that finished before the given time.
+func (q *Queue) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := q.db.NewDelete().
+		Model((*Job)(nil)).
+		Where("queue = ?", q.name).
+		Where("state IN (?, ?)", StateDone, StateDead).
+		Where("finished_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	return deleted, nil
+}
+
+func jobNotFound(id int64, err error) error {
+	errMsg := fmt.Sprintf("job %d not found", id)
+	return ez.New(ez.ENOTFOUND, errMsg, err)
+}
@@ -0,0 +1,158 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/vanclief/compose/components/logger"
+)
+
+const (
+	DefaultQueue           = "default"
+	DefaultWorkers         = 10
+	DefaultPollInterval    = time.Second
+	DefaultJobTimeout      = 15 * time.Minute
+	DefaultRescueAfter     = time.Hour
+	DefaultShutdownTimeout = 60 * time.Second
+	DefaultMaxAttempts     = 10
+	DefaultUpdateTimeout   = 10 * time.Second
+)
+
+// Option configures the Queue.
+type Option func(*Queue)
+
+// WithName sets the queue this Queue enqueues to and works on by default.
+func WithName(name string) Option {
+	return func(q *Queue) {
+		if name != "" {
+			q.name = name
+		}
+	}
+}
+
+// WithWorkers sets how many jobs are handled at the same time.
+func WithWorkers(n int) Option {
+	return func(q *Queue) {
+		if n > 0 {
+			q.workers = n
+		}
+	}
+}
+
+// WithPollInterval sets how often idle workers look for new jobs.
+func WithPollInterval(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.pollInterval = d
+		}
+	}
+}
+
+// WithJobTimeout sets the context deadline of every handler call.
+func WithJobTimeout(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.jobTimeout = d
+		}
+	}
+}
+
+// WithRescueAfter sets how long a job may stay running before it's considered abandoned
+// (e.g. its worker crashed) and made available again. Must be longer than the job timeout.
+func WithRescueAfter(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.rescueAfter = d
+		}
+	}
+}
+
+// WithShutdownTimeout sets how long Start() waits for running jobs after ctx cancel.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.shutdownTimeout = d
+		}
+	}
+}
+
+// WithMaxAttempts sets the default number of attempts before a failing job is dead.
+func WithMaxAttempts(n int) Option {
+	return func(q *Queue) {
+		if n > 0 {
+			q.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the wait before retrying a job that failed its given attempt (1 for the first).
+// Nil => DefaultBackoff.
+func WithBackoff(fn func(attempt int) time.Duration) Option {
+	return func(q *Queue) {
+		if fn == nil {
+			q.backoff = DefaultBackoff
+			return
+		}
+		q.backoff = fn
+	}
+}
+
+// WithLogger sets the logger. Nil => Noop logger.
+func WithLogger(l logger.Logger) Option {
+	return func(q *Queue) {
+		if l == nil {
+			q.log = logger.Noop{}
+			return
+		}
+		q.log = l
+	}
+}
+
+// EnqueueOption configures a single enqueued job.
+type EnqueueOption func(*Job)
+
+// RunAt delays the job until t.
+func RunAt(t time.Time) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt = t
+	}
+}
+
+// RunIn delays the job by d.
+func RunIn(d time.Duration) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt = time.Now().Add(d)
+	}
+}
+
+// Priority sets the job priority; higher priorities are claimed first. Defaults to 0.
+func Priority(p int) EnqueueOption {
+	return func(j *Job) {
+		j.Priority = p
+	}
+}
+
+// MaxAttempts overrides the queue's WithMaxAttempts for the job.
+func MaxAttempts(n int) EnqueueOption {
+	return func(j *Job) {
+		if n > 0 {
+			j.MaxAttempts = n
+		}
+	}
+}
+
+// UniqueKey makes enqueueing fail with ez.ECONFLICT while another pending or
+// running job has the same key.
+func UniqueKey(key string) EnqueueOption {
+	return func(j *Job) {
+		j.UniqueKey = key
+	}
+}
+
+// OnQueue enqueues the job on another queue than the Queue's own.
+func OnQueue(name string) EnqueueOption {
+	return func(j *Job) {
+		if name != "" {
+			j.Queue = name
+		}
+	}
+}
@@ -0,0 +1,518 @@
+// Package queue is a durable background job queue on top of Postgres.
+// Jobs are claimed with SELECT ... FOR UPDATE SKIP LOCKED, so any number of
+// workers on any number of replicas can share a queue.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/logger"
+	"github.com/vanclief/compose/drivers/databases/relational"
+	"github.com/vanclief/ez"
+)
+
+// handlerFunc decodes the payload of a claimed job and handles it.
+type handlerFunc func(ctx context.Context, job *Job) error
+
+// Queue enqueues jobs and runs a pool of workers that handle them.
+type Queue struct {
+	db              *relational.DB
+	name            string
+	workers         int
+	pollInterval    time.Duration
+	jobTimeout      time.Duration
+	rescueAfter     time.Duration
+	shutdownTimeout time.Duration
+	maxAttempts     int
+	backoff         func(attempt int) time.Duration
+	log             logger.Logger
+	owner           string                 // written on claimed jobs
+	mu              sync.RWMutex           // protects handlers
+	handlers        map[string]handlerFunc // key: payload kind
+}
+
+// New creates the queue table and its indexes if they don't exist.
+func New(db *relational.DB, opts ...Option) (*Queue, error) {
+	if db == nil {
+		return nil, ez.New(ez.EINVALID, "db cannot be nil", nil)
+	}
+
+	host, _ := os.Hostname()
+	q := &Queue{
+		db:              db,
+		name:            DefaultQueue,
+		workers:         DefaultWorkers,
+		pollInterval:    DefaultPollInterval,
+		jobTimeout:      DefaultJobTimeout,
+		rescueAfter:     DefaultRescueAfter,
+		shutdownTimeout: DefaultShutdownTimeout,
+		maxAttempts:     DefaultMaxAttempts,
+		backoff:         DefaultBackoff,
+		log:             logger.Noop{},
+		owner:           fmt.Sprintf("%s-%s", host, uuid.NewString()[:8]),
+		handlers:        make(map[string]handlerFunc),
+	}
+
+	for _, o := range opts {
+		o(q)
+	}
+
+	if q.rescueAfter <= q.jobTimeout {
+		return nil, ez.New(ez.EINVALID, "rescue after must be longer than the job timeout", nil)
+	}
+
+	err := q.createSchema()
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	return q, nil
+}
+
+// createSchema creates the jobs table, the index used to claim jobs, the index used
+// to rescue abandoned jobs and the unique index enforcing UniqueKey.
+func (q *Queue) createSchema() error {
+	err := q.db.CreateTables([]interface{}{(*Job)(nil)})
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	ctx := context.Background()
+
+	_, err = q.db.NewCreateIndex().
+		Model((*Job)(nil)).
+		Index("queue_jobs_claim_idx").
+		ColumnExpr("queue, priority DESC, run_at, id").
+		Where("state = ?", StatePending).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	_, err = q.db.NewCreateIndex().
+		Model((*Job)(nil)).
+		Index("queue_jobs_rescue_idx").
+		Column("queue", "locked_at").
+		Where("state = ?", StateRunning).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	_, err = q.db.NewCreateIndex().
+		Model((*Job)(nil)).
+		Unique().
+		Index("queue_jobs_unique_key_idx").
+		Column("unique_key").
+		Where("unique_key IS NOT NULL").
+		Where("state IN (?)", bun.In([]State{StatePending, StateRunning})).
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	return nil
+}
+
+// Register sets the handler of payloads of type T, dispatched by their Kind.
+// T should be a struct with a value receiver Kind method. Handlers must be registered before Start.
+// Returning an error retries the job with backoff, see Permanent to give up right away.
+func Register[T Payload](q *Queue, handler func(ctx context.Context, job *Job, payload T) error) error {
+	if handler == nil {
+		return ez.New(ez.EINVALID, "handler cannot be nil", nil)
+	}
+
+	var zero T
+	kind := zero.Kind()
+	if kind == "" {
+		return ez.New(ez.EINVALID, "payload kind cannot be empty", nil)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.handlers[kind]; exists {
+		errMsg := fmt.Sprintf("handler for job kind %s already registered", kind)
+		return ez.New(ez.ECONFLICT, errMsg, nil)
+	}
+
+	q.handlers[kind] = func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			errMsg := fmt.Sprintf("invalid payload for job kind %s", kind)
+			return Permanent(ez.New(ez.EINVALID, errMsg, err))
+		}
+		return handler(ctx, job, payload)
+	}
+
+	return nil
+}
+
+// Enqueue stores a job to be handled by the handler registered for its payload's Kind.
+// It returns ez.ECONFLICT if the job has a UniqueKey that is already pending or running.
+func (q *Queue) Enqueue(ctx context.Context, payload Payload, opts ...EnqueueOption) (*Job, error) {
+	return q.EnqueueTx(ctx, q.db, payload, opts...)
+}
+
+// EnqueueTx is like Enqueue within the given transaction, so the job is only
+// visible to workers if the transaction commits.
+func (q *Queue) EnqueueTx(ctx context.Context, db bun.IDB, payload Payload, opts ...EnqueueOption) (*Job, error) {
+	if payload == nil {
+		return nil, ez.New(ez.EINVALID, "payload cannot be nil", nil)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, ez.New(ez.EINVALID, "could not encode job payload", err)
+	}
+
+	job := &Job{
+		Queue:       q.name,
+		Kind:        payload.Kind(),
+		Payload:     data,
+		State:       StatePending,
+		MaxAttempts: q.maxAttempts,
+	}
+	for _, o := range opts {
+		o(job)
+	}
+
+	query := db.NewInsert().
+		Model(job).
+		On("CONFLICT DO NOTHING").
+		Returning("*")
+
+	// use the database clock, like claim does
+	if job.RunAt.IsZero() {
+		query = query.Value("run_at", "now()")
+	}
+
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+	if affected == 0 {
+		errMsg := fmt.Sprintf("job with unique key %s is already enqueued", job.UniqueKey)
+		return nil, ez.New(ez.ECONFLICT, errMsg, nil)
+	}
+
+	return job, nil
+}
+
+// Start claims and handles jobs with the registered handlers until ctx is canceled.
+// Running jobs are not canceled with ctx: Start waits up to the shutdown timeout for
+// them, then cancels their context. Jobs that still don't finish are rescued later.
+func (q *Queue) Start(ctx context.Context) error {
+	kinds := q.kinds()
+	if len(kinds) == 0 {
+		return ez.New(ez.EINVALID, "no job handlers registered", nil)
+	}
+
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	var wg sync.WaitGroup
+	defer q.waitForJobs(&wg, cancelJobs)
+
+	sem := make(chan struct{}, q.workers)
+	freed := make(chan struct{}, 1)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var lastRescue time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		case <-freed:
+		}
+
+		if time.Since(lastRescue) >= q.rescueAfter/4 {
+			q.rescue(ctx)
+			lastRescue = time.Now()
+		}
+
+		wait := q.pollInterval
+		if free := q.workers - len(sem); free > 0 {
+			jobs, err := q.claim(ctx, kinds, free)
+			if err != nil && ctx.Err() == nil {
+				q.log.Warn().Str("queue", q.name).Err(err).Msg("Queue could not claim jobs")
+			}
+
+			for i := range jobs {
+				job := &jobs[i]
+				sem <- struct{}{}
+				wg.Add(1)
+				go func() {
+					defer func() {
+						<-sem
+						wg.Done()
+						select {
+						case freed <- struct{}{}:
+						default:
+						}
+					}()
+					q.work(jobCtx, job)
+				}()
+			}
+
+			// a full batch means more jobs are probably ready
+			if len(jobs) == free {
+				wait = 0
+			}
+		}
+
+		timer.Reset(wait)
+	}
+}
+
+// waitForJobs blocks until all running jobs finish or the shutdown timeout, then cancels them.
+func (q *Queue) waitForJobs(wg *sync.WaitGroup, cancelJobs context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(q.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		q.log.Warn().Str("queue", q.name).Msg("Queue: timed out waiting for jobs to finish, canceling them")
+		cancelJobs()
+	}
+}
+
+// kinds returns the payload kinds with a registered handler.
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// claim marks up to limit ready jobs as running by this Queue and returns them,
+// highest priority first. Rows locked by other workers are skipped.
+func (q *Queue) claim(ctx context.Context, kinds []string, limit int) ([]Job, error) {
+	ready := q.db.NewSelect().
+		Model((*Job)(nil)).
+		Column("id").
+		Where("queue = ?", q.name).
+		Where("state = ?", StatePending).
+		Where("run_at <= now()").
+		Where("kind IN (?)", bun.In(kinds)).
+		OrderExpr("priority DESC, run_at, id").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	jobs := []Job{}
+	_, err := q.db.NewUpdate().
+		Model((*Job)(nil)).
+		Set("state = ?", StateRunning).
+		Set("attempts = attempts + 1").
+		Set("locked_by = ?", q.owner).
+		Set("locked_at = now()").
+		Where("id IN (?)", ready).
+		Returning("*").
+		Exec(ctx, &jobs)
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Priority != jobs[j].Priority {
+			return jobs[i].Priority > jobs[j].Priority
+		}
+		return jobs[i].RunAt.Before(jobs[j].RunAt)
+	})
+
+	return jobs, nil
+}
+
+// work handles a claimed job and stores its outcome.
+func (q *Queue) work(ctx context.Context, job *Job) {
+	start := time.Now()
+	err := q.handle(ctx, job)
+	duration := time.Since(start)
+
+	// the job context may be done, store the outcome on a fresh one
+	updateCtx, cancel := context.WithTimeout(context.Background(), DefaultUpdateTimeout)
+	defer cancel()
+
+	if err == nil {
+		if err := q.complete(updateCtx, job); err != nil {
+			q.log.Error().Int64("job_id", job.ID).Str("kind", job.Kind).Err(err).Msg("Queue could not mark job as done")
+			return
+		}
+		q.log.Info().
+			Int64("job_id", job.ID).
+			Str("kind", job.Kind).
+			Int("attempt", job.Attempts).
+			Dur("duration", duration).
+			Msg("Queue job done")
+		return
+	}
+
+	dead, updateErr := q.fail(updateCtx, job, err)
+	if updateErr != nil {
+		q.log.Error().Int64("job_id", job.ID).Str("kind", job.Kind).Err(updateErr).Msg("Queue could not mark job as failed")
+		return
+	}
+
+	event := q.log.Warn()
+	msg := "Queue job failed, will retry"
+	if dead {
+		event = q.log.Error()
+		msg = "Queue job failed permanently"
+	}
+	event.
+		Int64("job_id", job.ID).
+		Str("kind", job.Kind).
+		Int("attempt", job.Attempts).
+		Int("max_attempts", job.MaxAttempts).
+		Dur("duration", duration).
+		Err(err).
+		Msg(msg)
+}
+
+// handle calls the job's handler with the job timeout, turning panics into errors.
+func (q *Queue) handle(ctx context.Context, job *Job) (err error) {
+	q.mu.RLock()
+	handler := q.handlers[job.Kind]
+	q.mu.RUnlock()
+
+	if handler == nil {
+		errMsg := fmt.Sprintf("no handler registered for job kind %s", job.Kind)
+		return Permanent(ez.New(ez.ENOTFOUND, errMsg, nil))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.jobTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			q.log.Error().
+				Int64("job_id", job.ID).
+				Str("kind", job.Kind).
+				Any("panic", r).
+				Bytes("stack", debug.Stack()).
+				Msg("Queue job panic")
+			err = ez.New(ez.EINTERNAL, fmt.Sprintf("job panic: %v", r), nil)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// complete marks a job claimed by this Queue as done.
+func (q *Queue) complete(ctx context.Context, job *Job) error {
+	_, err := q.db.NewUpdate().
+		Model((*Job)(nil)).
+		Set("state = ?", StateDone).
+		Set("finished_at = now()").
+		Set("locked_by = NULL").
+		Set("locked_at = NULL").
+		Where("id = ?", job.ID).
+		Where("locked_by = ?", q.owner).
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	return nil
+}
+
+// fail schedules a retry of a job claimed by this Queue, or marks it as dead if the
+// error is permanent or the job ran out of attempts. Returns true if the job is dead.
+func (q *Queue) fail(ctx context.Context, job *Job, jobErr error) (bool, error) {
+	dead := isPermanent(jobErr) || job.Attempts >= job.MaxAttempts
+
+	query := q.db.NewUpdate().
+		Model((*Job)(nil)).
+		Set("last_error = ?", jobErr.Error()).
+		Set("locked_by = NULL").
+		Set("locked_at = NULL").
+		Where("id = ?", job.ID).
+		Where("locked_by = ?", q.owner)
+
+	if dead {
+		query = query.
+			Set("state = ?", StateDead).
+			Set("finished_at = now()")
+	} else {
+		backoff := q.backoff(job.Attempts)
+		query = query.
+			Set("state = ?", StatePending).
+			Set("run_at = now() + ? * interval '1 millisecond'", backoff.Milliseconds())
+	}
+
+	_, err := query.Exec(ctx)
+	if err != nil {
+		return false, ez.Wrap(err)
+	}
+
+	return dead, nil
+}
+
+// rescue makes jobs that have been running for longer than rescueAfter available again,
+// or dead if they ran out of attempts. Their worker most likely crashed.
+func (q *Queue) rescue(ctx context.Context) {
+	res, err := q.db.NewUpdate().
+		Model((*Job)(nil)).
+		Set("state = CASE WHEN attempts >= max_attempts THEN ? ELSE ? END", StateDead, StatePending).
+		Set("finished_at = CASE WHEN attempts >= max_attempts THEN now() END").
+		Set("last_error = ?", "rescued after its worker stopped responding").
+		Set("locked_by = NULL").
+		Set("locked_at = NULL").
+		Where("queue = ?", q.name).
+		Where("state = ?", StateRunning).
+		Where("locked_at < now() - ? * interval '1 millisecond'", q.rescueAfter.Milliseconds()).
+		Exec(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			q.log.Warn().Str("queue", q.name).Err(err).Msg("Queue could not rescue abandoned jobs")
+		}
+		return
+	}
+
+	if rescued, _ := res.RowsAffected(); rescued > 0 {
+		q.log.Warn().Str("queue", q.name).Int64("jobs", rescued).Msg("Queue rescued abandoned jobs")
+	}
+}
+
+// DefaultBackoff waits 15s after the first failed attempt and doubles up to an hour, ±10%.
+func DefaultBackoff(attempt int) time.Duration {
+	d := 15 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	d = min(d, time.Hour)
+
+	// spread retries of jobs that failed together
+	return d - d/10 + rand.N(d/5)
+}
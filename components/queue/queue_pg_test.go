@@ -0,0 +1,251 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+func (suite *TestSuite) newQueue(opts ...Option) *Queue {
+	q, err := New(suite.db, opts...)
+	suite.Require().NoError(err)
+	return q
+}
+
+func (suite *TestSuite) TestClaimExclusive() {
+	ctx := context.Background()
+	queues := []*Queue{suite.newQueue(), suite.newQueue(), suite.newQueue()}
+
+	for i := range 30 {
+		_, err := queues[0].Enqueue(ctx, exportPayload{ReportID: int64(i)})
+		suite.Require().NoError(err)
+	}
+
+	var mu sync.Mutex
+	claimedBy := make(map[int64]string)
+	var wg sync.WaitGroup
+	for _, q := range queues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				jobs, err := q.claim(ctx, []string{"export"}, 4)
+				suite.NoError(err)
+				if len(jobs) == 0 {
+					return
+				}
+
+				mu.Lock()
+				for _, job := range jobs {
+					_, dup := claimedBy[job.ID]
+					suite.False(dup, "job %d claimed twice", job.ID)
+					suite.Equal(q.owner, job.LockedBy)
+					suite.Equal(StateRunning, job.State)
+					suite.Equal(1, job.Attempts)
+					claimedBy[job.ID] = q.owner
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.Len(claimedBy, 30)
+}
+
+func (suite *TestSuite) TestClaimOrder() {
+	ctx := context.Background()
+	q := suite.newQueue()
+
+	low, err := q.Enqueue(ctx, exportPayload{ReportID: 1})
+	suite.Require().NoError(err)
+	high, err := q.Enqueue(ctx, exportPayload{ReportID: 2}, Priority(10))
+	suite.Require().NoError(err)
+	_, err = q.Enqueue(ctx, exportPayload{ReportID: 3}, RunIn(time.Hour))
+	suite.Require().NoError(err)
+	_, err = q.Enqueue(ctx, exportPayload{ReportID: 4}, OnQueue("emails"))
+	suite.Require().NoError(err)
+
+	// delayed jobs and jobs of other queues are not claimed
+	jobs, err := q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 2)
+	suite.Equal(high.ID, jobs[0].ID)
+	suite.Equal(low.ID, jobs[1].ID)
+}
+
+func (suite *TestSuite) TestUniqueKey() {
+	ctx := context.Background()
+	q := suite.newQueue()
+
+	job, err := q.Enqueue(ctx, exportPayload{ReportID: 42}, UniqueKey("report-42"))
+	suite.Require().NoError(err)
+
+	_, err = q.Enqueue(ctx, exportPayload{ReportID: 42}, UniqueKey("report-42"))
+	suite.Equal(ez.ECONFLICT, ez.ErrorCode(err))
+
+	// jobs without a key are never deduplicated
+	for range 2 {
+		_, err = q.Enqueue(ctx, exportPayload{ReportID: 42})
+		suite.Require().NoError(err)
+	}
+
+	// still unique while running
+	suite.Require().NoError(Register(q, func(ctx context.Context, job *Job, payload exportPayload) error {
+		return nil
+	}))
+	jobs, err := q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 3)
+
+	_, err = q.Enqueue(ctx, exportPayload{ReportID: 42}, UniqueKey("report-42"))
+	suite.Equal(ez.ECONFLICT, ez.ErrorCode(err))
+
+	// the key is free again once the job is done
+	for i := range jobs {
+		q.work(ctx, &jobs[i])
+	}
+
+	done, err := q.Get(ctx, job.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StateDone, done.State)
+
+	_, err = q.Enqueue(ctx, exportPayload{ReportID: 42}, UniqueKey("report-42"))
+	suite.NoError(err)
+}
+
+func (suite *TestSuite) TestRetryAndDead() {
+	ctx := context.Background()
+	backoff := time.Hour
+	q := suite.newQueue(WithBackoff(func(attempt int) time.Duration { return backoff }))
+
+	suite.Require().NoError(Register(q, func(ctx context.Context, job *Job, payload exportPayload) error {
+		if payload.ReportID == 0 {
+			return Permanent(errors.New("report not found"))
+		}
+		return errors.New("smtp unavailable")
+	}))
+
+	job, err := q.Enqueue(ctx, exportPayload{ReportID: 1}, MaxAttempts(2))
+	suite.Require().NoError(err)
+
+	jobs, err := q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 1)
+	q.work(ctx, &jobs[0])
+
+	// retried after the backoff
+	retry, err := q.Get(ctx, job.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StatePending, retry.State)
+	suite.Equal(1, retry.Attempts)
+	suite.Equal("smtp unavailable", retry.LastError)
+	suite.Empty(retry.LockedBy)
+	suite.WithinDuration(time.Now().Add(backoff), retry.RunAt, time.Minute)
+
+	jobs, err = q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Empty(jobs)
+
+	// out of attempts on the second failure
+	backoff = 0
+	_, err = suite.db.NewUpdate().Model((*Job)(nil)).Set("run_at = now()").Where("id = ?", job.ID).Exec(ctx)
+	suite.Require().NoError(err)
+
+	jobs, err = q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 1)
+	suite.Equal(2, jobs[0].Attempts)
+	q.work(ctx, &jobs[0])
+
+	dead, err := q.Get(ctx, job.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StateDead, dead.State)
+	suite.Equal(2, dead.Attempts)
+	suite.False(dead.FinishedAt.IsZero())
+
+	// permanent errors are not retried
+	job, err = q.Enqueue(ctx, exportPayload{ReportID: 0})
+	suite.Require().NoError(err)
+
+	jobs, err = q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 1)
+	q.work(ctx, &jobs[0])
+
+	dead, err = q.Get(ctx, job.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StateDead, dead.State)
+	suite.Equal(1, dead.Attempts)
+	suite.Equal("report not found", dead.LastError)
+}
+
+func (suite *TestSuite) TestRescue() {
+	ctx := context.Background()
+	rescueAfter := 200 * time.Millisecond
+	q := suite.newQueue(WithJobTimeout(rescueAfter/2), WithRescueAfter(rescueAfter))
+
+	retried, err := q.Enqueue(ctx, exportPayload{ReportID: 1})
+	suite.Require().NoError(err)
+	lastAttempt, err := q.Enqueue(ctx, exportPayload{ReportID: 2}, MaxAttempts(1))
+	suite.Require().NoError(err)
+
+	jobs, err := q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 2)
+
+	// not abandoned yet
+	q.rescue(ctx)
+	job, err := q.Get(ctx, retried.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StateRunning, job.State)
+
+	// the worker crashed without storing the outcome
+	time.Sleep(rescueAfter + 50*time.Millisecond)
+	q.rescue(ctx)
+
+	job, err = q.Get(ctx, retried.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StatePending, job.State)
+	suite.Empty(job.LockedBy)
+	suite.NotEmpty(job.LastError)
+
+	job, err = q.Get(ctx, lastAttempt.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StateDead, job.State)
+	suite.False(job.FinishedAt.IsZero())
+
+	// the crashed worker can't overwrite the outcome of the rescued job
+	suite.Require().NoError(q.complete(ctx, &jobs[0]))
+	jobs, err = q.claim(ctx, []string{"export"}, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(jobs, 1)
+	suite.Equal(retried.ID, jobs[0].ID)
+	suite.Equal(2, jobs[0].Attempts)
+}
+
+func (suite *TestSuite) TestManageOwnQueue() {
+	ctx := context.Background()
+	q := suite.newQueue()
+	emails := suite.newQueue(WithName("emails"))
+
+	job, err := emails.Enqueue(ctx, exportPayload{ReportID: 1})
+	suite.Require().NoError(err)
+	_, err = suite.db.NewUpdate().Model((*Job)(nil)).Set("state = ?", StateDead).Where("id = ?", job.ID).Exec(ctx)
+	suite.Require().NoError(err)
+
+	// queues sharing the table only see their own jobs
+	_, err = q.Get(ctx, job.ID)
+	suite.Equal(ez.ENOTFOUND, ez.ErrorCode(err))
+	err = q.Retry(ctx, job.ID)
+	suite.Equal(ez.ENOTFOUND, ez.ErrorCode(err))
+
+	suite.Require().NoError(emails.Retry(ctx, job.ID))
+	retried, err := emails.Get(ctx, job.ID)
+	suite.Require().NoError(err)
+	suite.Equal(StatePending, retried.State)
+	suite.Zero(retried.Attempts)
+}
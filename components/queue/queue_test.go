@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger"
+	"github.com/vanclief/ez"
+)
+
+type exportPayload struct {
+	ReportID int64 `json:"report_id"`
+}
+
+func (exportPayload) Kind() string { return "export" }
+
+func newTestQueue() *Queue {
+	return &Queue{
+		jobTimeout: time.Minute,
+		log:        logger.Noop{},
+		handlers:   make(map[string]handlerFunc),
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	testCases := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 15 * time.Second},
+		{2, 30 * time.Second},
+		{5, 240 * time.Second},
+		{20, time.Hour},
+	}
+
+	for _, tc := range testCases {
+		for i := 0; i < 20; i++ {
+			d := DefaultBackoff(tc.attempt)
+			require.GreaterOrEqual(t, d, tc.expected-tc.expected/10)
+			require.Less(t, d, tc.expected+tc.expected/10)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	require.Nil(t, Permanent(nil))
+
+	err := ez.New(ez.EINVALID, "bad report", nil)
+	wrapped := Permanent(err)
+	require.True(t, isPermanent(wrapped))
+	require.True(t, errors.Is(wrapped, err))
+	require.False(t, isPermanent(err))
+}
+
+func TestRegister(t *testing.T) {
+	q := newTestQueue()
+
+	var got exportPayload
+	err := Register(q, func(ctx context.Context, job *Job, payload exportPayload) error {
+		got = payload
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = Register(q, func(ctx context.Context, job *Job, payload exportPayload) error { return nil })
+	require.Equal(t, ez.ECONFLICT, ez.ErrorCode(err))
+	require.Equal(t, []string{"export"}, q.kinds())
+
+	t.Run("decodes the payload", func(t *testing.T) {
+		err := q.handle(context.Background(), &Job{Kind: "export", Payload: []byte(`{"report_id":42}`)})
+		require.NoError(t, err)
+		require.Equal(t, exportPayload{ReportID: 42}, got)
+	})
+
+	t.Run("invalid payloads are not retried", func(t *testing.T) {
+		err := q.handle(context.Background(), &Job{Kind: "export", Payload: []byte(`{"report_id":"x"}`)})
+		require.True(t, isPermanent(err))
+	})
+
+	t.Run("unknown kinds are not retried", func(t *testing.T) {
+		err := q.handle(context.Background(), &Job{Kind: "import"})
+		require.True(t, isPermanent(err))
+	})
+}
+
+func TestHandleRecoversPanics(t *testing.T) {
+	q := newTestQueue()
+	err := Register(q, func(ctx context.Context, job *Job, payload exportPayload) error {
+		panic("boom")
+	})
+	require.NoError(t, err)
+
+	err = q.handle(context.Background(), &Job{Kind: "export", Payload: []byte(`{}`)})
+	require.Error(t, err)
+	require.False(t, isPermanent(err))
+}
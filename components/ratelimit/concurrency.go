@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrLimitReached is returned by ConcurrencyLimiter.Acquire when a key has no free
+// slot and no room left to wait for one.
+var ErrLimitReached = errors.New("ratelimit: too many operations in flight")
+
+// ConcurrencyLimiter caps how many operations of a key run at the same time.
+// Unlike a Limiter it doesn't limit how often operations start: a slot is held from
+// Acquire until its release func is called. Waiters are served in arrival order.
+type ConcurrencyLimiter struct {
+	mu         sync.Mutex
+	keys       map[string]*inFlight // idle keys are deleted
+	limit      int
+	maxWaiting int
+}
+
+type inFlight struct {
+	active  int
+	waiters list.List // of *waiter
+}
+
+type waiter struct {
+	ready chan struct{} // closed when the slot is handed to the waiter
+}
+
+// NewConcurrencyLimiter creates a ConcurrencyLimiter allowing limit operations per key
+// at once, with up to maxWaiting callers per key waiting for a slot; 0 => no waiting.
+func NewConcurrencyLimiter(limit, maxWaiting int) *ConcurrencyLimiter {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	return &ConcurrencyLimiter{
+		keys:       make(map[string]*inFlight),
+		limit:      limit,
+		maxWaiting: max(maxWaiting, 0),
+	}
+}
+
+// TryAcquire takes a slot of key if one is free, without waiting.
+// The returned release func must be called once the operation is done.
+func (cl *ConcurrencyLimiter) TryAcquire(key string) (release func(), ok bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	f := cl.keys[key]
+	if f == nil {
+		f = &inFlight{}
+		cl.keys[key] = f
+	}
+
+	if f.active >= cl.limit {
+		return nil, false
+	}
+
+	f.active++
+	return cl.releaser(key), true
+}
+
+// Acquire takes a slot of key, waiting for one to be released if the key is at its
+// limit. It returns ErrLimitReached if too many callers are already waiting, or the
+// context error if ctx ends first. The returned release func must be called once the
+// operation is done.
+func (cl *ConcurrencyLimiter) Acquire(ctx context.Context, key string) (release func(), err error) {
+	cl.mu.Lock()
+
+	f := cl.keys[key]
+	if f == nil {
+		f = &inFlight{}
+		cl.keys[key] = f
+	}
+
+	if f.active < cl.limit {
+		f.active++
+		cl.mu.Unlock()
+		return cl.releaser(key), nil
+	}
+
+	if f.waiters.Len() >= cl.maxWaiting {
+		cl.mu.Unlock()
+		return nil, ErrLimitReached
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	el := f.waiters.PushBack(w)
+	cl.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return cl.releaser(key), nil
+	case <-ctx.Done():
+	}
+
+	cl.mu.Lock()
+	select {
+	case <-w.ready:
+		// the slot was handed over while giving up, pass it on
+		cl.mu.Unlock()
+		cl.release(key)
+	default:
+		f.waiters.Remove(el)
+		cl.mu.Unlock()
+	}
+
+	return nil, ctx.Err()
+}
+
+// InFlight returns how many operations of key are running and waiting.
+func (cl *ConcurrencyLimiter) InFlight(key string) (active, waiting int) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	f := cl.keys[key]
+	if f == nil {
+		return 0, 0
+	}
+
+	return f.active, f.waiters.Len()
+}
+
+// releaser returns a release func of a slot of key that is safe to call more than once.
+func (cl *ConcurrencyLimiter) releaser(key string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { cl.release(key) })
+	}
+}
+
+// release hands the slot to the first waiter of key, or frees it.
+func (cl *ConcurrencyLimiter) release(key string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	f := cl.keys[key]
+	if f == nil {
+		return
+	}
+
+	if el := f.waiters.Front(); el != nil {
+		f.waiters.Remove(el)
+		close(el.Value.(*waiter).ready)
+		return
+	}
+
+	f.active--
+	if f.active == 0 {
+		delete(cl.keys, key)
+	}
+}
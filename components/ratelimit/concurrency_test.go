@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	cl := NewConcurrencyLimiter(2, 1)
+
+	release1, ok := cl.TryAcquire("tenant")
+	require.True(t, ok)
+	release2, err := cl.Acquire(context.Background(), "tenant")
+	require.NoError(t, err)
+
+	_, ok = cl.TryAcquire("tenant")
+	require.False(t, ok)
+
+	// other keys have their own slots
+	releaseOther, ok := cl.TryAcquire("other")
+	require.True(t, ok)
+	releaseOther()
+
+	acquired := make(chan func())
+	go func() {
+		release, _ := cl.Acquire(context.Background(), "tenant")
+		acquired <- release
+	}()
+
+	require.Eventually(t, func() bool {
+		_, waiting := cl.InFlight("tenant")
+		return waiting == 1
+	}, time.Second, time.Millisecond)
+
+	// the only waiting spot is taken
+	_, err = cl.Acquire(context.Background(), "tenant")
+	require.ErrorIs(t, err, ErrLimitReached)
+
+	// releasing twice only frees one slot, which goes to the waiter
+	release1()
+	release1()
+	release3 := <-acquired
+	require.NotNil(t, release3)
+
+	active, waiting := cl.InFlight("tenant")
+	require.Equal(t, 2, active)
+	require.Zero(t, waiting)
+
+	release2()
+	release3()
+	active, _ = cl.InFlight("tenant")
+	require.Zero(t, active)
+	require.Empty(t, cl.keys)
+}
+
+func TestConcurrencyLimiterCancel(t *testing.T) {
+	cl := NewConcurrencyLimiter(1, 5)
+
+	release, ok := cl.TryAcquire("tenant")
+	require.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := cl.Acquire(ctx, "tenant")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, waiting := cl.InFlight("tenant")
+	require.Zero(t, waiting)
+
+	release()
+	require.Empty(t, cl.keys)
+}
@@ -0,0 +1,30 @@
+package ratelimit
+
+import "time"
+
+// Limiter limits how often a key (user ID, API key, IP...) may perform an action.
+// Implementations MUST be safe for concurrent use.
+type Limiter interface {
+	// Allow reports whether one action may happen now for key, consuming quota if so.
+	Allow(key string) bool
+	// AllowN reports whether n actions may happen now for key, consuming quota only if all n fit.
+	// It's always false for n <= 0.
+	AllowN(key string, n int) bool
+	// Reserve is like AllowN but also reports the state of the key's quota.
+	Reserve(key string, n int) Reservation
+}
+
+// Reservation is the outcome of a Reserve call.
+type Reservation struct {
+	OK         bool          // whether the n actions were allowed
+	Limit      int           // maximum quota of a key
+	Remaining  int           // quota left after this call
+	ResetAt    time.Time     // when the key's quota is fully restored
+	RetryAfter time.Duration // wait before the same call can succeed, 0 if OK or if n is not within 1..Limit
+}
+
+var (
+	_ Limiter = (*WindowCounter)(nil)
+	_ Limiter = (*TokenBucket)(nil)
+	_ Limiter = (*SlidingWindow)(nil)
+)
@@ -0,0 +1,259 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNow returns a clock function and a way to move it forward.
+func fakeNow() (func() time.Time, func(time.Duration)) {
+	now := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestWindowCounter(t *testing.T) {
+	rl := NewWindowCounter(60, 3)
+	now, advance := fakeNow()
+	rl.now = now
+
+	require.True(t, rl.AllowN("user", 2))
+	res := rl.Reserve("user", 2)
+	require.False(t, res.OK)
+	require.Equal(t, 1, res.Remaining)
+	require.Equal(t, time.Minute, res.RetryAfter)
+
+	require.True(t, rl.Allow("user"))
+	require.False(t, rl.Allow("user"))
+	require.True(t, rl.Allow("other user"))
+
+	advance(time.Minute)
+	res = rl.Reserve("user", 1)
+	require.True(t, res.OK)
+	require.Equal(t, 2, res.Remaining)
+	require.Equal(t, now().Add(time.Minute), res.ResetAt)
+}
+
+func TestTokenBucket(t *testing.T) {
+	tb := NewTokenBucket(1, time.Second, 3)
+	now, advance := fakeNow()
+	tb.now = now
+
+	res := tb.Reserve("user", 3)
+	require.True(t, res.OK)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, now().Add(3*time.Second), res.ResetAt)
+
+	res = tb.Reserve("user", 2)
+	require.False(t, res.OK)
+	require.Equal(t, 2*time.Second, res.RetryAfter)
+
+	advance(time.Second)
+	require.True(t, tb.Allow("user"))
+	require.False(t, tb.Allow("user"))
+
+	// the bucket never holds more than burst tokens
+	advance(time.Hour)
+	require.True(t, tb.AllowN("user", 3))
+	require.False(t, tb.Allow("user"))
+
+	res = tb.Reserve("user", 4)
+	require.False(t, res.OK)
+	require.Zero(t, res.RetryAfter)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	sw := NewSlidingWindow(time.Minute, 10)
+	now, advance := fakeNow()
+	sw.now = now
+
+	// fill the window in its second half
+	advance(30 * time.Second)
+	require.True(t, sw.AllowN("user", 10))
+	require.False(t, sw.Allow("user"))
+
+	// a fixed window would allow 10 more right after the boundary
+	advance(30 * time.Second)
+	res := sw.Reserve("user", 5)
+	require.False(t, res.OK)
+	require.Equal(t, 30*time.Second, res.RetryAfter)
+
+	// halfway through, half of the previous window still counts
+	advance(30 * time.Second)
+	res = sw.Reserve("user", 5)
+	require.True(t, res.OK)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, now().Add(90*time.Second), res.ResetAt)
+
+	// the current window alone is full
+	res = sw.Reserve("user", 6)
+	require.False(t, res.OK)
+	require.Equal(t, 42*time.Second, res.RetryAfter)
+}
+
+func TestLimiterEviction(t *testing.T) {
+	wc := NewWindowCounter(60, 1, WithMaxKeys(2))
+	now, advance := fakeNow()
+	wc.now = now
+
+	require.True(t, wc.Allow("a"))
+	require.True(t, wc.Allow("b"))
+	require.False(t, wc.Allow("a"))
+
+	// b is the least recently used key, so c evicts it and it gets a fresh quota
+	require.True(t, wc.Allow("c"))
+	require.Equal(t, Stats{Keys: 2, Evicted: 1}, wc.Stats())
+	require.True(t, wc.Allow("b"))
+	require.Equal(t, Stats{Keys: 2, Evicted: 2}, wc.Stats())
+
+	// expired keys are dropped before evicting live ones
+	advance(time.Minute)
+	require.True(t, wc.Allow("d"))
+	require.Equal(t, Stats{Keys: 1, Expired: 2, Evicted: 2}, wc.Stats())
+}
+
+// newExpiryStore returns a store whose state is the time the key expires at.
+func newExpiryStore(o options) *store[time.Time] {
+	return newStore(o, func(expiresAt time.Time, now time.Time) bool {
+		return !now.Before(expiresAt)
+	})
+}
+
+func TestStoreSweep(t *testing.T) {
+	s := newExpiryStore(options{})
+	require.Len(t, s.shards, storeShards)
+
+	now, advance := fakeNow()
+	for i := range 200 {
+		s.get(fmt.Sprintf("user-%d", i), now(), func() time.Time { return now().Add(time.Minute) })
+	}
+	for _, shard := range s.shards {
+		require.NotZero(t, shard.lru.Len())
+	}
+
+	// traffic on a single key sweeps every shard in turn
+	advance(time.Minute)
+	for range storeShards {
+		s.get("user-0", now(), func() time.Time { return now().Add(time.Minute) })
+	}
+
+	keys := 0
+	for _, shard := range s.shards {
+		keys += shard.lru.Len()
+	}
+	require.Equal(t, 1, keys)
+	require.Equal(t, Stats{Keys: 1, Expired: 200}, s.Stats(now()))
+}
+
+func TestStoreShardedEviction(t *testing.T) {
+	require.Len(t, newExpiryStore(options{maxKeys: 1023}).shards, 1)
+
+	s := newExpiryStore(options{maxKeys: 1024})
+	require.Len(t, s.shards, storeShards)
+
+	now, _ := fakeNow()
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				s.get(fmt.Sprintf("user-%d-%d", w, i), now(), func() time.Time { return now().Add(time.Hour) })
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := s.Stats(now())
+	require.LessOrEqual(t, stats.Keys, 1024)
+	require.Greater(t, stats.Keys, 900)
+	require.Equal(t, uint64(8000-stats.Keys), stats.Evicted)
+	require.Zero(t, stats.Expired)
+}
+
+type statsLimiter interface {
+	Limiter
+	Stats() Stats
+}
+
+func TestLimiterExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		limiter   func(now func() time.Time) statsLimiter
+		expiresIn time.Duration
+	}{
+		{
+			name: "window counter",
+			limiter: func(now func() time.Time) statsLimiter {
+				wc := NewWindowCounter(60, 10)
+				wc.now = now
+				return wc
+			},
+			expiresIn: time.Minute,
+		},
+		{
+			name: "token bucket",
+			limiter: func(now func() time.Time) statsLimiter {
+				tb := NewTokenBucket(10, time.Minute, 0)
+				tb.now = now
+				return tb
+			},
+			expiresIn: 6 * time.Second,
+		},
+		{
+			name: "sliding window",
+			limiter: func(now func() time.Time) statsLimiter {
+				sw := NewSlidingWindow(time.Minute, 10)
+				sw.now = now
+				return sw
+			},
+			expiresIn: 2 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, advance := fakeNow()
+			l := tt.limiter(now)
+
+			require.True(t, l.Allow("a"))
+			require.True(t, l.Allow("b"))
+			require.Equal(t, Stats{Keys: 2}, l.Stats())
+
+			advance(tt.expiresIn - time.Nanosecond)
+			require.True(t, l.Allow("c"))
+			require.Equal(t, Stats{Keys: 3}, l.Stats())
+
+			advance(time.Nanosecond)
+			require.True(t, l.Allow("d"))
+			require.Equal(t, Stats{Keys: 2, Expired: 2}, l.Stats())
+		})
+	}
+}
+
+func TestLimiterInvalidN(t *testing.T) {
+	limiters := map[string]Limiter{
+		"window counter": NewWindowCounter(60, 3),
+		"token bucket":   NewTokenBucket(3, time.Minute, 0),
+		"sliding window": NewSlidingWindow(time.Minute, 3),
+	}
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, l.AllowN("user", 3))
+
+			for _, n := range []int{0, -100} {
+				res := l.Reserve("user", n)
+				require.False(t, res.OK, n)
+				require.Zero(t, res.RetryAfter, n)
+				require.Equal(t, 3, res.Limit, n)
+			}
+
+			// no quota was given back
+			require.False(t, l.Allow("user"))
+		})
+	}
+}
@@ -0,0 +1,269 @@
+// Package pgstore implements ratelimit.Limiter in Postgres, so every replica of a
+// service shares the same limits.
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/logger"
+	"github.com/vanclief/compose/components/ratelimit"
+	"github.com/vanclief/compose/drivers/databases/relational"
+	"github.com/vanclief/ez"
+)
+
+const (
+	DefaultName          = "default"
+	DefaultTimeout       = 100 * time.Millisecond
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// RateLimitKey is the row holding the theoretical arrival time of a key.
+type RateLimitKey struct {
+	bun.BaseModel `bun:"table:ratelimit_keys,alias:rate_limit"`
+
+	Name string    `bun:"name,pk"`
+	Key  string    `bun:"key,pk"`
+	TAT  time.Time `bun:"tat,notnull"`
+}
+
+// Limiter implements ratelimit.Limiter with the generic cell rate algorithm (GCRA):
+// a key only stores the time at which its bucket would be full again, updated with a
+// single atomic upsert. Times are computed with the database clock, so replica clock
+// skew doesn't matter.
+//
+// When the database fails, decisions are made by a local fallback limiter for the
+// retry interval, so an outage degrades limits to per-replica instead of failing requests.
+type Limiter struct {
+	db            *relational.DB
+	name          string
+	interval      time.Duration // time to earn one action
+	burst         int
+	timeout       time.Duration
+	retryInterval time.Duration
+	fallback      ratelimit.Limiter
+	log           logger.Logger
+	downUntil     atomic.Int64 // unix nanoseconds until which the fallback is used
+}
+
+// Option configures the Limiter.
+type Option func(*Limiter)
+
+// WithName sets the name keys are stored under, so limiters with different limits
+// can share the table. Limiters with the same name must use the same limits.
+func WithName(name string) Option {
+	return func(l *Limiter) {
+		if name != "" {
+			l.name = name
+		}
+	}
+}
+
+// WithTimeout sets how long a decision may wait for the database before falling back.
+func WithTimeout(d time.Duration) Option {
+	return func(l *Limiter) {
+		if d > 0 {
+			l.timeout = d
+		}
+	}
+}
+
+// WithRetryInterval sets how long the fallback is used after a database error.
+func WithRetryInterval(d time.Duration) Option {
+	return func(l *Limiter) {
+		if d > 0 {
+			l.retryInterval = d
+		}
+	}
+}
+
+// WithFallback sets the limiter used while the database is unavailable.
+// Defaults to a ratelimit.TokenBucket with the same limits.
+func WithFallback(fallback ratelimit.Limiter) Option {
+	return func(l *Limiter) {
+		if fallback != nil {
+			l.fallback = fallback
+		}
+	}
+}
+
+// WithLogger sets the logger database errors are reported to.
+func WithLogger(log logger.Logger) Option {
+	return func(l *Limiter) {
+		if log != nil {
+			l.log = log
+		}
+	}
+}
+
+// NewLimiter creates the keys table if it doesn't exist and returns a Limiter allowing
+// limit actions every period, with bursts of up to burst actions; 0 => limit.
+func NewLimiter(db *relational.DB, limit int, period time.Duration, burst int, opts ...Option) (*Limiter, error) {
+	if db == nil {
+		return nil, ez.New(ez.EINVALID, "db cannot be nil", nil)
+	}
+
+	if limit <= 0 {
+		limit = ratelimit.DEFAULT_LIMIT
+	}
+
+	if period <= 0 {
+		period = ratelimit.DEFAULT_WINDOW * time.Second
+	}
+
+	if burst <= 0 {
+		burst = limit
+	}
+
+	l := &Limiter{
+		db:            db,
+		name:          DefaultName,
+		interval:      period / time.Duration(limit),
+		burst:         burst,
+		timeout:       DefaultTimeout,
+		retryInterval: DefaultRetryInterval,
+		log:           logger.Noop{},
+	}
+
+	for _, o := range opts {
+		o(l)
+	}
+
+	if l.interval <= 0 {
+		return nil, ez.New(ez.EINVALID, "period is too short for the limit", nil)
+	}
+
+	if l.fallback == nil {
+		l.fallback = ratelimit.NewTokenBucket(limit, period, burst)
+	}
+
+	err := db.CreateTables([]interface{}{(*RateLimitKey)(nil)})
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	return l, nil
+}
+
+// Allow implements ratelimit.Limiter.
+func (l *Limiter) Allow(key string) bool {
+	return l.Reserve(key, 1).OK
+}
+
+// AllowN implements ratelimit.Limiter.
+func (l *Limiter) AllowN(key string, n int) bool {
+	return l.Reserve(key, n).OK
+}
+
+// Reserve implements ratelimit.Limiter.
+func (l *Limiter) Reserve(key string, n int) ratelimit.Reservation {
+	// n <= 0 would move tat backwards, giving quota back
+	if n <= 0 || n > l.burst {
+		return ratelimit.Reservation{Limit: l.burst, ResetAt: time.Now()}
+	}
+
+	if time.Now().UnixNano() < l.downUntil.Load() {
+		return l.fallback.Reserve(key, n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	res, err := l.reserve(ctx, key, n)
+	if err != nil {
+		l.downUntil.Store(time.Now().Add(l.retryInterval).UnixNano())
+		l.log.Warn().Str("limiter", l.name).Err(err).Msg("Rate limiter could not reach the database, limiting locally")
+		return l.fallback.Reserve(key, n)
+	}
+
+	return res
+}
+
+// reserve takes n actions from key if they fit, otherwise it reads the state of key
+// to report when they will.
+func (l *Limiter) reserve(ctx context.Context, key string, n int) (ratelimit.Reservation, error) {
+	cost := micros(time.Duration(n) * l.interval)
+	capacity := micros(time.Duration(l.burst) * l.interval)
+
+	var tat, now time.Time
+	err := l.db.NewInsert().
+		Model(&RateLimitKey{Name: l.name, Key: key}).
+		Value("tat", "now() + ? * interval '1 microsecond'", cost).
+		On("CONFLICT (name, key) DO UPDATE").
+		Set("tat = GREATEST(rate_limit.tat, now()) + ? * interval '1 microsecond'", cost).
+		Where("GREATEST(rate_limit.tat, now()) + ? * interval '1 microsecond' <= now() + ? * interval '1 microsecond'", cost, capacity).
+		Returning("tat, now()").
+		Scan(ctx, &tat, &now)
+	if err == nil {
+		return l.reservation(tat, now, n, true), nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return ratelimit.Reservation{}, ez.Wrap(err)
+	}
+
+	err = l.db.NewSelect().
+		Model((*RateLimitKey)(nil)).
+		ColumnExpr("tat, now()").
+		Where("name = ?", l.name).
+		Where("key = ?", key).
+		Scan(ctx, &tat, &now)
+	if errors.Is(err, sql.ErrNoRows) {
+		// pruned since the upsert
+		tat, now = time.Time{}, time.Time{}
+	} else if err != nil {
+		return ratelimit.Reservation{}, ez.Wrap(err)
+	}
+
+	return l.reservation(tat, now, n, false), nil
+}
+
+// reservation describes the state of a key whose theoretical arrival time is tat at
+// the database time now. Times are moved to the local clock.
+func (l *Limiter) reservation(tat, now time.Time, n int, ok bool) ratelimit.Reservation {
+	capacity := time.Duration(l.burst) * l.interval
+	backlog := max(tat.Sub(now), 0)
+
+	res := ratelimit.Reservation{
+		OK:        ok,
+		Limit:     l.burst,
+		Remaining: int((capacity - backlog) / l.interval),
+		ResetAt:   time.Now().Add(backlog),
+	}
+
+	if !ok {
+		res.RetryAfter = max(backlog+time.Duration(n)*l.interval-capacity, 0)
+	}
+
+	return res
+}
+
+// Prune deletes the keys whose bucket is full again, they behave like new keys.
+// Call it periodically, e.g. from a scheduler job, to keep the table small.
+func (l *Limiter) Prune(ctx context.Context) (int, error) {
+	res, err := l.db.NewDelete().
+		Model((*RateLimitKey)(nil)).
+		Where("name = ?", l.name).
+		Where("tat <= now()").
+		Exec(ctx)
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	return int(affected), nil
+}
+
+// micros returns d in whole microseconds, the precision of Postgres timestamps.
+func micros(d time.Duration) int64 {
+	return int64(math.Ceil(float64(d) / float64(time.Microsecond)))
+}
+
+var _ ratelimit.Limiter = (*Limiter)(nil)
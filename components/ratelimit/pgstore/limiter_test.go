@@ -0,0 +1,170 @@
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/vanclief/compose/components/logger"
+	"github.com/vanclief/compose/components/ratelimit"
+	"github.com/vanclief/compose/drivers/databases/relational"
+)
+
+func newTestLimiter(limit int, period time.Duration, burst int) *Limiter {
+	return &Limiter{
+		name:          DefaultName,
+		interval:      period / time.Duration(limit),
+		burst:         burst,
+		timeout:       DefaultTimeout,
+		retryInterval: DefaultRetryInterval,
+		fallback:      ratelimit.NewTokenBucket(limit, period, burst),
+		log:           logger.Noop{},
+	}
+}
+
+func TestReservation(t *testing.T) {
+	l := newTestLimiter(10, time.Minute, 5)
+	now := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		tat        time.Time
+		n          int
+		ok         bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{"first action", now.Add(6 * time.Second), 1, true, 4, 0},
+		{"bucket emptied", now.Add(30 * time.Second), 1, true, 0, 0},
+		{"bucket full", now.Add(30 * time.Second), 1, false, 0, 6 * time.Second},
+		{"not enough tokens", now.Add(20 * time.Second), 3, false, 1, 8 * time.Second},
+		{"key pruned", time.Time{}, 1, false, 5, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbNow := now
+			if tc.tat.IsZero() {
+				dbNow = time.Time{}
+			}
+
+			res := l.reservation(tc.tat, dbNow, tc.n, tc.ok)
+			require.Equal(t, tc.ok, res.OK)
+			require.Equal(t, 5, res.Limit)
+			require.Equal(t, tc.remaining, res.Remaining)
+			require.Equal(t, tc.retryAfter, res.RetryAfter)
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	sqldb := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithAddr("127.0.0.1:1"),
+		pgdriver.WithDialTimeout(50*time.Millisecond),
+	))
+	defer sqldb.Close()
+
+	l := newTestLimiter(2, time.Minute, 2)
+	l.db = &relational.DB{DB: bun.NewDB(sqldb, pgdialect.New())}
+
+	require.True(t, l.Allow("user"))
+	require.NotZero(t, l.downUntil.Load())
+	require.True(t, l.Allow("user"))
+	require.False(t, l.Allow("user"))
+
+	// more than the burst is never allowed
+	res := l.Reserve("user", 3)
+	require.False(t, res.OK)
+	require.Zero(t, res.RetryAfter)
+
+	// neither are n <= 0, which would give quota back
+	for _, n := range []int{0, -100} {
+		res = l.Reserve("user", n)
+		require.False(t, res.OK, n)
+		require.Zero(t, res.RetryAfter, n)
+	}
+	require.False(t, l.Allow("user"))
+}
+
+func (suite *TestSuite) newLimiter(limit int, period time.Duration, burst int, opts ...Option) *Limiter {
+	// a slow test database must not be mistaken for an outage
+	opts = append([]Option{WithTimeout(time.Second)}, opts...)
+
+	l, err := NewLimiter(suite.db, limit, period, burst, opts...)
+	suite.Require().NoError(err)
+	return l
+}
+
+func (suite *TestSuite) TestBurst() {
+	l := suite.newLimiter(10, time.Minute, 5)
+
+	for i := range 5 {
+		res := l.Reserve("user", 1)
+		suite.Require().True(res.OK, i)
+		suite.Equal(5, res.Limit)
+		suite.Equal(4-i, res.Remaining)
+	}
+
+	res := l.Reserve("user", 1)
+	suite.False(res.OK)
+	suite.Zero(res.Remaining)
+	suite.InDelta(6*time.Second, res.RetryAfter, float64(time.Second))
+
+	// other keys have their own bucket
+	suite.True(l.AllowN("admin", 5))
+	suite.False(l.Allow("admin"))
+
+	// decisions were made by the database, not the fallback
+	suite.Zero(l.downUntil.Load())
+}
+
+func (suite *TestSuite) TestSharedLimit() {
+	replicas := []*Limiter{
+		suite.newLimiter(10, time.Minute, 5),
+		suite.newLimiter(10, time.Minute, 5),
+	}
+
+	allowed := 0
+	for i := range 10 {
+		if replicas[i%2].Allow("user") {
+			allowed++
+		}
+	}
+	suite.Equal(5, allowed)
+
+	// limiters with another name don't share keys
+	other := suite.newLimiter(10, time.Minute, 5, WithName("exports"))
+	suite.True(other.Allow("user"))
+
+	for _, l := range append(replicas, other) {
+		suite.Zero(l.downUntil.Load())
+	}
+}
+
+func (suite *TestSuite) TestRefillAndPrune() {
+	l := suite.newLimiter(2, 200*time.Millisecond, 2)
+
+	suite.Require().True(l.AllowN("user", 2))
+	suite.Require().False(l.Allow("user"))
+
+	// one action is earned every 100ms
+	time.Sleep(120 * time.Millisecond)
+	suite.True(l.Allow("user"))
+	suite.False(l.Allow("user"))
+
+	pruned, err := l.Prune(context.Background())
+	suite.Require().NoError(err)
+	suite.Zero(pruned)
+
+	time.Sleep(250 * time.Millisecond)
+	pruned, err = l.Prune(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(1, pruned)
+
+	suite.True(l.AllowN("user", 2))
+}
@@ -0,0 +1,41 @@
+package pgstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vanclief/compose/drivers/databases/relational"
+	"github.com/vanclief/compose/drivers/databases/relational/postgres"
+)
+
+type TestSuite struct {
+	suite.Suite
+	db *relational.DB
+}
+
+func (suite *TestSuite) SetupTest() {
+	cfg := &postgres.ConnectionConfig{
+		Username: "postgres",
+		Password: "",
+		Host:     "localhost:5432",
+		Database: "compose_test",
+	}
+
+	db, err := postgres.ConnectToDatabase(cfg)
+	suite.Require().NoError(err)
+
+	err = db.ResetTables([]interface{}{(*RateLimitKey)(nil)})
+	suite.Require().NoError(err)
+
+	suite.db = db
+}
+
+func (suite *TestSuite) TearDownTest() {
+	if suite.db != nil {
+		suite.db.Close()
+	}
+}
+
+func TestSuiteRun(t *testing.T) {
+	suite.Run(t, new(TestSuite))
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// SlidingWindow implements a sliding window counter: the count of the previous
+// fixed window is weighted by how much of it still overlaps the sliding window,
+// which smooths out the 2x burst a WindowCounter allows at window boundaries
+// while keeping two counters per key.
+type SlidingWindow struct {
+	counters *store[*slidingCounter]
+	window   time.Duration
+	limit    int
+	now      func() time.Time
+}
+
+type slidingCounter struct {
+	mu       sync.Mutex
+	start    time.Time // start of the current fixed window
+	current  int
+	previous int
+}
+
+// NewSlidingWindow creates a SlidingWindow allowing limit actions per window.
+func NewSlidingWindow(window time.Duration, limit int, opts ...Option) *SlidingWindow {
+	if window <= 0 {
+		window = DEFAULT_WINDOW * time.Second
+	}
+
+	if limit <= 0 {
+		limit = DEFAULT_LIMIT
+	}
+
+	// both windows of a counter are over once it's two windows old
+	expired := func(c *slidingCounter, now time.Time) bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return now.Sub(c.start) >= 2*window
+	}
+
+	return &SlidingWindow{
+		counters: newStore(newOptions(opts), expired),
+		window:   window,
+		limit:    limit,
+		now:      time.Now,
+	}
+}
+
+// Allow implements Limiter.
+func (sw *SlidingWindow) Allow(key string) bool {
+	return sw.Reserve(key, 1).OK
+}
+
+// AllowN implements Limiter.
+func (sw *SlidingWindow) AllowN(key string, n int) bool {
+	return sw.Reserve(key, n).OK
+}
+
+// Reserve implements Limiter.
+func (sw *SlidingWindow) Reserve(key string, n int) Reservation {
+	now := sw.now()
+	start := now.Truncate(sw.window)
+
+	c := sw.counters.get(key, now, func() *slidingCounter {
+		return &slidingCounter{start: start}
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// move to the current fixed window
+	if !c.start.Equal(start) {
+		if start.Sub(c.start) == sw.window {
+			c.previous = c.current
+		} else {
+			c.previous = 0
+		}
+		c.current = 0
+		c.start = start
+	}
+
+	// weight of the previous window still inside the sliding window
+	weight := 1 - float64(now.Sub(start))/float64(sw.window)
+	estimate := float64(c.previous)*weight + float64(c.current)
+
+	res := Reservation{Limit: sw.limit}
+	switch {
+	case n <= 0:
+		// never allowed, it would decrement the counter
+	case estimate+float64(n) <= float64(sw.limit):
+		c.current += n
+		estimate += float64(n)
+		res.OK = true
+	case n <= sw.limit:
+		res.RetryAfter = sw.retryAfter(c, now, n)
+	}
+
+	res.Remaining = max(sw.limit-int(math.Ceil(estimate)), 0)
+	switch {
+	case c.current > 0:
+		res.ResetAt = start.Add(2 * sw.window)
+	case c.previous > 0:
+		res.ResetAt = start.Add(sw.window)
+	default:
+		res.ResetAt = now
+	}
+	return res
+}
+
+// retryAfter returns how long until n more actions fit in the sliding window.
+func (sw *SlidingWindow) retryAfter(c *slidingCounter, now time.Time, n int) time.Duration {
+	// wait for the previous window to slide out enough
+	at := c.start
+	count, rest := c.previous, sw.limit-n-c.current
+
+	// the current window alone is too full, wait for it to become the previous one
+	if rest < 0 {
+		at = c.start.Add(sw.window)
+		count, rest = c.current, sw.limit-n
+	}
+
+	// solve count * (1 - elapsed/window) <= rest for elapsed
+	elapsed := time.Duration(math.Ceil((1 - float64(rest)/float64(count)) * float64(sw.window)))
+	return at.Add(elapsed).Sub(now)
+}
+
+// Stats returns how many keys the window tracks and how many it dropped.
+func (sw *SlidingWindow) Stats() Stats {
+	return sw.counters.Stats(sw.now())
+}
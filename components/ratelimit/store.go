@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats describes the keys a limiter keeps in memory.
+type Stats struct {
+	Keys    int    `json:"keys"`    // keys currently tracked
+	Expired uint64 `json:"expired"` // keys dropped because their state expired
+	Evicted uint64 `json:"evicted"` // keys dropped to stay under the max key count
+}
+
+// Option configures a limiter.
+type Option func(*options)
+
+type options struct {
+	maxKeys int
+}
+
+// WithMaxKeys caps how many keys a limiter tracks; past it the least recently used
+// key is evicted, which resets its quota. Caps of 1024 keys or more are split evenly
+// over the limiter's shards, so the evicted key is the least recently used of its shard.
+// 0 => unlimited.
+func WithMaxKeys(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.maxKeys = n
+		}
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+const (
+	// storeShards is the number of shards of a store, each with its own lock and LRU.
+	storeShards = 16
+	// minShardKeys is the smallest per shard key cap; stores capped lower use a single
+	// shard, so their eviction order stays exact.
+	minShardKeys = 64
+)
+
+// store keeps the state of every key, split over shards by key hash so concurrent
+// keys rarely share a lock. Each shard keeps its keys in least recently used order and
+// drops expired state from the tail on every access; every access also sweeps another
+// shard in turn, so memory follows the number of active keys even in quiet shards.
+type store[T any] struct {
+	seed   maphash.Seed
+	shards []*storeShard[T]
+	next   atomic.Uint32 // shard swept on the next access
+}
+
+type storeShard[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is the most recently used
+	maxKeys int
+	expired func(state T, now time.Time) bool
+	stats   Stats
+}
+
+type storeEntry[T any] struct {
+	key   string
+	state T
+}
+
+// newStore returns a store of up to o.maxKeys keys. Sharded stores split the cap
+// evenly, so the least recently used key of the key's shard is evicted.
+func newStore[T any](o options, expired func(state T, now time.Time) bool) *store[T] {
+	n := storeShards
+	if o.maxKeys > 0 && o.maxKeys < storeShards*minShardKeys {
+		n = 1
+	}
+
+	s := &store[T]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*storeShard[T], n),
+	}
+	for i := range s.shards {
+		s.shards[i] = &storeShard[T]{
+			entries: make(map[string]*list.Element),
+			lru:     list.New(),
+			maxKeys: o.maxKeys / n,
+			expired: expired,
+		}
+	}
+
+	return s
+}
+
+// get returns the state of key, created with create if the key isn't tracked.
+func (s *store[T]) get(key string, now time.Time, create func() T) T {
+	shard := s.shards[0]
+	if len(s.shards) > 1 {
+		shard = s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+	}
+
+	state := shard.get(key, now, create)
+
+	// a busy shard is swept by its own traffic, skip it
+	other := s.shards[s.next.Add(1)%uint32(len(s.shards))]
+	if other != shard && other.mu.TryLock() {
+		other.sweep(now)
+		other.mu.Unlock()
+	}
+
+	return state
+}
+
+func (s *storeShard[T]) get(key string, now time.Time, create func() T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// key itself may be dropped, expired state is the same as new state
+	s.sweep(now)
+
+	if el, ok := s.entries[key]; ok {
+		s.lru.MoveToFront(el)
+		return el.Value.(*storeEntry[T]).state
+	}
+
+	if s.maxKeys > 0 && s.lru.Len() >= s.maxKeys {
+		s.remove(s.lru.Back())
+		s.stats.Evicted++
+	}
+
+	state := create()
+	s.entries[key] = s.lru.PushFront(&storeEntry[T]{key: key, state: state})
+	return state
+}
+
+// sweep drops expired keys from the least recently used end, stopping at the first live one.
+func (s *storeShard[T]) sweep(now time.Time) {
+	for el := s.lru.Back(); el != nil; el = s.lru.Back() {
+		if !s.expired(el.Value.(*storeEntry[T]).state, now) {
+			return
+		}
+		s.remove(el)
+		s.stats.Expired++
+	}
+}
+
+func (s *storeShard[T]) remove(el *list.Element) {
+	s.lru.Remove(el)
+	delete(s.entries, el.Value.(*storeEntry[T]).key)
+}
+
+// Stats drops the expired keys of every shard, then returns the current key count and
+// how many keys were dropped so far.
+func (s *store[T]) Stats(now time.Time) Stats {
+	var stats Stats
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+		shard.sweep(now)
+		stats.Keys += shard.lru.Len()
+		stats.Expired += shard.stats.Expired
+		stats.Evicted += shard.stats.Evicted
+		shard.mu.Unlock()
+	}
+	return stats
+}
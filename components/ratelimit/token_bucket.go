@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket implements a token bucket per key: every key starts with a full bucket
+// of burst tokens, refilled at limit tokens per period. Each action takes a token,
+// so short bursts are allowed while the long-term rate stays at limit per period.
+type TokenBucket struct {
+	buckets *store[*bucket]
+	rate    float64 // tokens per second
+	burst   int
+	now     func() time.Time
+}
+
+type bucket struct {
+	mu      sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+// NewTokenBucket creates a TokenBucket refilling limit tokens every period.
+// burst is the bucket capacity; 0 => limit.
+func NewTokenBucket(limit int, period time.Duration, burst int, opts ...Option) *TokenBucket {
+	if limit <= 0 {
+		limit = DEFAULT_LIMIT
+	}
+
+	if period <= 0 {
+		period = DEFAULT_WINDOW * time.Second
+	}
+
+	if burst <= 0 {
+		burst = limit
+	}
+
+	tb := &TokenBucket{
+		rate:  float64(limit) / period.Seconds(),
+		burst: burst,
+		now:   time.Now,
+	}
+
+	// a bucket that refilled completely is the same as a new one
+	tb.buckets = newStore(newOptions(opts), func(b *bucket, now time.Time) bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.tokens+now.Sub(b.updated).Seconds()*tb.rate >= float64(tb.burst)
+	})
+
+	return tb
+}
+
+// Allow implements Limiter.
+func (tb *TokenBucket) Allow(key string) bool {
+	return tb.Reserve(key, 1).OK
+}
+
+// AllowN implements Limiter.
+func (tb *TokenBucket) AllowN(key string, n int) bool {
+	return tb.Reserve(key, n).OK
+}
+
+// Reserve implements Limiter.
+func (tb *TokenBucket) Reserve(key string, n int) Reservation {
+	now := tb.now()
+
+	b := tb.buckets.get(key, now, func() *bucket {
+		return &bucket{tokens: float64(tb.burst), updated: now}
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// refill for the time elapsed since the last call
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(float64(tb.burst), b.tokens+elapsed.Seconds()*tb.rate)
+		b.updated = now
+	}
+
+	res := Reservation{Limit: tb.burst}
+	switch {
+	case n <= 0:
+		// never allowed, it would add tokens back
+	case b.tokens >= float64(n):
+		b.tokens -= float64(n)
+		res.OK = true
+	case n <= tb.burst:
+		res.RetryAfter = tb.durationFor(float64(n) - b.tokens)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.ResetAt = now.Add(tb.durationFor(float64(tb.burst) - b.tokens))
+	return res
+}
+
+// durationFor returns how long it takes to refill the given number of tokens.
+func (tb *TokenBucket) durationFor(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / tb.rate * float64(time.Second)))
+}
+
+// Stats returns how many keys the bucket tracks and how many it dropped.
+func (tb *TokenBucket) Stats() Stats {
+	return tb.buckets.Stats(tb.now())
+}
@@ -10,11 +10,13 @@ const (
 	DEFAULT_LIMIT  = 60
 )
 
-// WindowCounter implements a simple window counter
+// WindowCounter implements a simple fixed window counter.
+// A key can make up to 2x the limit around a window boundary, see SlidingWindow.
 type WindowCounter struct {
-	usersCounter sync.Map
+	usersCounter *store[*UserCounter]
 	window       time.Duration
 	limit        int
+	now          func() time.Time
 }
 
 type UserCounter struct {
@@ -23,7 +25,7 @@ type UserCounter struct {
 	currentCount int
 }
 
-func NewWindowCounter(windowSeconds, limit int) *WindowCounter {
+func NewWindowCounter(windowSeconds, limit int, opts ...Option) *WindowCounter {
 	if windowSeconds == 0 {
 		windowSeconds = DEFAULT_WINDOW
 	}
@@ -34,17 +36,37 @@ func NewWindowCounter(windowSeconds, limit int) *WindowCounter {
 
 	window := time.Duration(windowSeconds) * time.Second
 
+	// a counter whose window has passed is reset on its next use anyway
+	expired := func(uc *UserCounter, now time.Time) bool {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		return now.Sub(uc.firstRequest) >= window
+	}
+
 	return &WindowCounter{
-		window: window,
-		limit:  limit,
+		usersCounter: newStore(newOptions(opts), expired),
+		window:       window,
+		limit:        limit,
+		now:          time.Now,
 	}
 }
 
 func (rl *WindowCounter) Allow(userID string) bool {
-	now := time.Now()
+	return rl.Reserve(userID, 1).OK
+}
+
+// AllowN implements Limiter.
+func (rl *WindowCounter) AllowN(userID string, n int) bool {
+	return rl.Reserve(userID, n).OK
+}
 
-	value, _ := rl.usersCounter.LoadOrStore(userID, &UserCounter{firstRequest: now})
-	userCounter := value.(*UserCounter)
+// Reserve implements Limiter.
+func (rl *WindowCounter) Reserve(userID string, n int) Reservation {
+	now := rl.now()
+
+	userCounter := rl.usersCounter.get(userID, now, func() *UserCounter {
+		return &UserCounter{firstRequest: now}
+	})
 
 	userCounter.mu.Lock()
 	defer userCounter.mu.Unlock()
@@ -52,16 +74,31 @@ func (rl *WindowCounter) Allow(userID string) bool {
 	// If window has passed, reset the counter
 	elapsed := now.Sub(userCounter.firstRequest)
 	if elapsed >= rl.window {
-		userCounter.currentCount = 1
+		userCounter.currentCount = 0
 		userCounter.firstRequest = now
-		return true
 	}
 
-	// If the counter is below the limit, increment the counter
-	if userCounter.currentCount < rl.limit {
-		userCounter.currentCount++
-		return true
+	res := Reservation{
+		Limit:   rl.limit,
+		ResetAt: userCounter.firstRequest.Add(rl.window),
+	}
+
+	// If the counter stays within the limit, increment the counter
+	switch {
+	case n <= 0:
+		// never allowed, it would decrement the counter
+	case userCounter.currentCount+n <= rl.limit:
+		userCounter.currentCount += n
+		res.OK = true
+	case n <= rl.limit:
+		res.RetryAfter = res.ResetAt.Sub(now)
 	}
 
-	return false
+	res.Remaining = rl.limit - userCounter.currentCount
+	return res
+}
+
+// Stats returns how many keys the counter tracks and how many it dropped.
+func (rl *WindowCounter) Stats() Stats {
+	return rl.usersCounter.Stats(rl.now())
 }
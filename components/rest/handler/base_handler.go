@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"github.com/vanclief/compose/components/logger"
 	"github.com/vanclief/compose/components/rest/requests"
 )
 
@@ -18,8 +19,19 @@ type BaseHandler struct {
 	// status always come from the original error. Returning nil keeps the
 	// original error untouched.
 	ErrorTranslator func(err error, request requests.Request) error
+
+	// Logger, when set, receives the request errors. Defaults to logger.FromContext.
+	Logger logger.Logger
 }
 
 func NewHandler(App App) *BaseHandler {
 	return &BaseHandler{App: App}
 }
+
+// logger returns the Logger of the handler with the log fields of the request context.
+func (h *BaseHandler) logger(request requests.Request) logger.Logger {
+	if h.Logger == nil {
+		return logger.FromContext(request.GetContext())
+	}
+	return h.Logger.Ctx(request.GetContext())
+}
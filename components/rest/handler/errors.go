@@ -1,10 +1,11 @@
 package handler
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/rs/zerolog/log"
+	"github.com/vanclief/compose/components/logger"
 	"github.com/vanclief/compose/components/rest/requests"
 	"github.com/vanclief/ez"
 )
@@ -13,19 +14,28 @@ import (
 func (h *BaseHandler) ManageError(c echo.Context, op string, request requests.Request, err error) error {
 	code := ez.ErrorCode(err)
 
-	log.Error().
+	log := h.logger(request)
+
+	event := log.Error().
 		Str("id", request.GetID()).
-		Type("body_type", request.GetBody()).
+		Str("body_type", fmt.Sprintf("%T", request.GetBody())).
 		Str("latency", time.Since(request.GetCreatedAt()).String()).
 		Str("error_code", code).
-		Str("error_message", ez.ErrorMessage(err)).
-		Str("request_client", request.GetClient()).
-		Str("request_ip", request.GetIP()).
-		Interface("request_json", request.GetBody()).
-		Msg("Request Error")
+		Str("error_message", ez.ErrorMessage(err))
+
+	// requests.New already attaches them to the request context, don't log them twice
+	ctx := request.GetContext()
+	if _, ok := logger.ContextField(ctx, "request_client"); !ok {
+		event = event.Str("request_client", request.GetClient())
+	}
+	if _, ok := logger.ContextField(ctx, "request_ip"); !ok {
+		event = event.Str("request_ip", request.GetIP())
+	}
+
+	event.Any("request_json", request.GetBody()).Msg("Request Error")
 
 	if code == ez.EINTERNAL {
-		LogErrorStacktrace(err)
+		logErrorStacktrace(log, err)
 		h.reportErrorToSentry(c, request, err)
 	}
 
@@ -42,12 +52,17 @@ func (h *BaseHandler) ManageError(c echo.Context, op string, request requests.Re
 	return c.JSON(status, ErrorResponse{Error: stdErr})
 }
 
+// LogErrorStacktrace logs every error of the ez chain of err at debug level with logger.Default.
 func LogErrorStacktrace(err error) {
+	logErrorStacktrace(logger.Default(), err)
+}
+
+func logErrorStacktrace(log logger.Logger, err error) {
 	if err == nil {
 		return
 	} else if e, ok := err.(*ez.Error); ok {
 		log.Debug().Msg(e.String())
-		LogErrorStacktrace(e.Err)
+		logErrorStacktrace(log, e.Err)
 	} else {
 		log.Debug().Msg(err.Error())
 	}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger/logtest"
+	"github.com/vanclief/compose/components/rest/requests"
+	"github.com/vanclief/ez"
+)
+
+func TestManageErrorLogsRequest(t *testing.T) {
+	header := http.Header{}
+	header.Set("Client", "web")
+
+	testCases := []struct {
+		name    string
+		request requests.Request
+	}{
+		{"fields in the context", requests.New(header, "10.0.0.1")},
+		{"fields not in the context", &requests.StandardRequest{
+			ID:      "req-1",
+			Client:  "web",
+			IP:      "10.0.0.1",
+			Context: context.Background(),
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := logtest.New()
+			h := &BaseHandler{Logger: rec}
+
+			e := echo.New()
+			resp := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/exports", nil), resp)
+
+			err := h.ManageError(c, "handler.Export", tc.request, ez.New(ez.ENOTFOUND, "report not found", nil))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusNotFound, resp.Code)
+
+			entries := rec.Find(logtest.LevelError, "Request Error",
+				"id", tc.request.GetID(),
+				"request_client", "web",
+				"request_ip", "10.0.0.1",
+				"error_code", ez.ENOTFOUND,
+			)
+			require.Len(t, entries, 1, rec.String())
+
+			// logged once, either with the event or the context
+			_, inFields := entries[0].Fields["request_client"]
+			_, inContext := entries[0].Context["request_client"]
+			require.NotEqual(t, inFields, inContext)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vanclief/compose/components/ratelimit"
+	"github.com/vanclief/compose/components/rest/handler"
+)
+
+// ConcurrencyConfig configures the Concurrency middleware.
+type ConcurrencyConfig struct {
+	// Limiter caps the requests of a key running at once. Required.
+	Limiter *ratelimit.ConcurrencyLimiter
+	// KeyExtractor picks the key requests are limited by. Defaults to DefaultKeyExtractor.
+	KeyExtractor KeyExtractor
+	// PerRoute limits every route separately when the middleware is set on a group or the server.
+	PerRoute bool
+	// Skipper skips the middleware for some requests, e.g. health checks.
+	Skipper func(c echo.Context) bool
+}
+
+// Concurrency limits how many requests of a key are handled at the same time, e.g. to
+// run a single export per tenant. Requests past the limit wait for a slot if the
+// limiter allows waiting; those that can't wait or reach their deadline first get an
+// HTTP 429 StandardError, and those whose client disconnects get no response:
+//
+//	api.POST("/exports", h.Export, middleware.Concurrency(middleware.ConcurrencyConfig{
+//		Limiter: ratelimit.NewConcurrencyLimiter(1, 0),
+//	}))
+func Concurrency(cfg ConcurrencyConfig) echo.MiddlewareFunc {
+	if cfg.Limiter == nil {
+		panic("concurrency middleware requires a limiter")
+	}
+	if cfg.KeyExtractor == nil {
+		cfg.KeyExtractor = DefaultKeyExtractor
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper != nil && cfg.Skipper(c) {
+				return next(c)
+			}
+
+			key := limitKey(c, cfg.KeyExtractor, cfg.PerRoute)
+			if key == "" {
+				return next(c)
+			}
+
+			release, err := cfg.Limiter.Acquire(c.Request().Context(), key)
+			switch {
+			case errors.Is(err, context.Canceled):
+				// the client went away while waiting, there's no one to answer
+				return nil
+			case err != nil:
+				// too many waiting, or the request deadline passed while waiting
+				stdErr := handler.StandardError{
+					Code:      CodeTooManyRequests,
+					Message:   "Too many requests in progress, please try again later",
+					RequestID: requestID(c),
+				}
+				return c.JSON(http.StatusTooManyRequests, handler.ErrorResponse{Error: stdErr})
+			}
+			defer release()
+
+			return next(c)
+		}
+	}
+}
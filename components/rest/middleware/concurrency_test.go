@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/ratelimit"
+)
+
+func TestConcurrency(t *testing.T) {
+	limiter := ratelimit.NewConcurrencyLimiter(1, 0)
+	started, done := make(chan struct{}), make(chan struct{})
+
+	e := echo.New()
+	e.POST("/exports", func(c echo.Context) error {
+		close(started)
+		<-done
+		return c.NoContent(http.StatusOK)
+	}, Concurrency(ConcurrencyConfig{Limiter: limiter}))
+
+	apiKey := http.Header{"Api-Key": []string{"key-1"}}
+
+	first := make(chan int)
+	go func() {
+		first <- serve(e, http.MethodPost, "/exports", apiKey).Code
+	}()
+	<-started
+
+	rec := serve(e, http.MethodPost, "/exports", apiKey)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Contains(t, rec.Body.String(), CodeTooManyRequests)
+
+	close(done)
+	require.Equal(t, http.StatusOK, <-first)
+
+	require.Eventually(t, func() bool {
+		active, _ := limiter.InFlight("api-key:key-1")
+		return active == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestConcurrencyWaitEnds(t *testing.T) {
+	limiter := ratelimit.NewConcurrencyLimiter(1, 1)
+	release, err := limiter.Acquire(context.Background(), "api-key:key-1")
+	require.NoError(t, err)
+	defer release()
+
+	e := echo.New()
+	mw := Concurrency(ConcurrencyConfig{Limiter: limiter})
+	h := mw(func(c echo.Context) error {
+		t.Error("handler must not run")
+		return nil
+	})
+
+	wait := func(ctx context.Context) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/exports", nil).WithContext(ctx)
+		req.Header.Set("Api-Key", "key-1")
+		rec := httptest.NewRecorder()
+		return rec, h(e.NewContext(req, rec))
+	}
+
+	t.Run("client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			for {
+				if _, waiting := limiter.InFlight("api-key:key-1"); waiting == 1 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+
+		// not an error echo would answer with a 500
+		rec, err := wait(ctx)
+		require.NoError(t, err)
+		require.Empty(t, rec.Body.String())
+	})
+
+	t.Run("deadline passes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		rec, err := wait(ctx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, rec.Code)
+		require.Contains(t, rec.Body.String(), CodeTooManyRequests)
+	})
+
+	_, waiting := limiter.InFlight("api-key:key-1")
+	require.Zero(t, waiting)
+}
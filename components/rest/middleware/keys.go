@@ -0,0 +1,58 @@
+// Package middleware provides Echo middlewares for services built on the rest package.
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// KeyExtractor returns the key a request is limited by, or "" to leave the request unlimited.
+type KeyExtractor func(c echo.Context) string
+
+// KeyByAPIKey keys requests by their API-KEY authentication header.
+func KeyByAPIKey(c echo.Context) string {
+	if key := c.Request().Header.Get("API-KEY"); key != "" {
+		return "api-key:" + key
+	}
+	return ""
+}
+
+// KeyByUser keys requests by the ID of the authenticated user, stored by the
+// authentication middleware as a map with an "id" entry under "user", either
+// on the Echo context or on the request context.
+func KeyByUser(c echo.Context) string {
+	user, ok := c.Get("user").(map[string]interface{})
+	if !ok {
+		user, ok = c.Request().Context().Value("user").(map[string]interface{})
+	}
+	if !ok {
+		return ""
+	}
+
+	if id, ok := user["id"].(string); ok && id != "" {
+		return "user:" + id
+	}
+	return ""
+}
+
+// KeyByIP keys requests by the client IP.
+func KeyByIP(c echo.Context) string {
+	if ip := c.RealIP(); ip != "" {
+		return "ip:" + ip
+	}
+	return ""
+}
+
+// KeyChain returns the key of the first extractor that finds one.
+func KeyChain(extractors ...KeyExtractor) KeyExtractor {
+	return func(c echo.Context) string {
+		for _, extract := range extractors {
+			if key := extract(c); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// DefaultKeyExtractor keys requests by API key, then authenticated user, then client IP.
+var DefaultKeyExtractor = KeyChain(KeyByAPIKey, KeyByUser, KeyByIP)
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vanclief/compose/components/logger"
+	"github.com/vanclief/compose/components/ratelimit"
+	"github.com/vanclief/compose/components/rest/handler"
+)
+
+// CodeTooManyRequests is the StandardError code of requests rejected by RateLimit.
+const CodeTooManyRequests = "too_many_requests"
+
+// RateLimitConfig configures the RateLimit middleware.
+type RateLimitConfig struct {
+	// Limiter decides whether a request is allowed. Required.
+	Limiter ratelimit.Limiter
+	// KeyExtractor picks the key requests are limited by. Defaults to DefaultKeyExtractor.
+	KeyExtractor KeyExtractor
+	// PerRoute limits every route separately when the middleware is set on a group or the server.
+	PerRoute bool
+	// Skipper skips the middleware for some requests, e.g. health checks.
+	Skipper func(c echo.Context) bool
+}
+
+// RateLimit limits requests with the configured Limiter. Every response carries the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers; rejected requests get
+// an HTTP 429 StandardError and a Retry-After header. Limits apply to the routes or group
+// the middleware is set on:
+//
+//	api := e.Group("/api", middleware.RateLimit(middleware.RateLimitConfig{
+//		Limiter: ratelimit.NewTokenBucket(100, time.Minute, 20),
+//	}))
+//	api.POST("/exports", h.Export, middleware.RateLimit(middleware.RateLimitConfig{
+//		Limiter: ratelimit.NewSlidingWindow(time.Hour, 10),
+//	}))
+func RateLimit(cfg RateLimitConfig) echo.MiddlewareFunc {
+	if cfg.Limiter == nil {
+		panic("rate limit middleware requires a limiter")
+	}
+	if cfg.KeyExtractor == nil {
+		cfg.KeyExtractor = DefaultKeyExtractor
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper != nil && cfg.Skipper(c) {
+				return next(c)
+			}
+
+			key := limitKey(c, cfg.KeyExtractor, cfg.PerRoute)
+			if key == "" {
+				return next(c)
+			}
+
+			res := cfg.Limiter.Reserve(key, 1)
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			header.Set("RateLimit-Reset", seconds(time.Until(res.ResetAt)))
+
+			if res.OK {
+				return next(c)
+			}
+
+			if res.RetryAfter > 0 {
+				header.Set("Retry-After", seconds(res.RetryAfter))
+			}
+
+			stdErr := handler.StandardError{
+				Code:      CodeTooManyRequests,
+				Message:   "Too many requests, please try again later",
+				RequestID: requestID(c),
+			}
+			return c.JSON(http.StatusTooManyRequests, handler.ErrorResponse{Error: stdErr})
+		}
+	}
+}
+
+// limitKey returns the key a request is limited by, "" to not limit it.
+func limitKey(c echo.Context, extract KeyExtractor, perRoute bool) string {
+	key := extract(c)
+	if key != "" && perRoute {
+		key = c.Request().Method + " " + c.Path() + " " + key
+	}
+	return key
+}
+
+// seconds formats d as a whole number of seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(max(d, 0).Seconds())), 10)
+}
+
+// requestID returns the request_id field of the request context, set by RequestID,
+// else the ID set by Echo's RequestID middleware or sent by the client, if any.
+func requestID(c echo.Context) string {
+	if v, ok := logger.ContextField(c.Request().Context(), "request_id"); ok {
+		if id, ok := v.(string); ok && id != "" {
+			return id
+		}
+	}
+
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
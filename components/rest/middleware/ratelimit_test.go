@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/ratelimit"
+	"github.com/vanclief/compose/components/rest/handler"
+	"github.com/vanclief/compose/components/rest/requests"
+)
+
+func ok(c echo.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+func serve(e *echo.Echo, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	e := echo.New()
+	e.GET("/reports", ok, RateLimit(RateLimitConfig{Limiter: ratelimit.NewWindowCounter(60, 2)}))
+
+	apiKey := http.Header{"Api-Key": []string{"key-1"}}
+
+	rec := serve(e, http.MethodGet, "/reports", apiKey)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+
+	rec = serve(e, http.MethodGet, "/reports", apiKey)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, http.MethodGet, "/reports", apiKey)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	var body handler.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, CodeTooManyRequests, body.Error.Code)
+
+	// other clients have their own quota
+	rec = serve(e, http.MethodGet, "/reports", http.Header{"Api-Key": []string{"key-2"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(e, http.MethodGet, "/reports", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRateLimitRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(RequestID())
+
+	limiter := ratelimit.NewWindowCounter(60, 1)
+	e.GET("/reports", func(c echo.Context) error {
+		request := requests.NewFromContext(c.Request().Context(), c.Request().Header, c.RealIP())
+		return c.String(http.StatusOK, request.GetID())
+	}, RateLimit(RateLimitConfig{Limiter: limiter}))
+
+	apiKey := http.Header{"Api-Key": []string{"key-1"}, echo.HeaderXRequestID: []string{"client-1"}}
+
+	// the handler's request reuses the ID of the middleware
+	rec := serve(e, http.MethodGet, "/reports", apiKey)
+	require.Equal(t, http.StatusOK, rec.Code)
+	firstID := rec.Header().Get(echo.HeaderXRequestID)
+	require.NotEmpty(t, firstID)
+	require.Equal(t, firstID, rec.Body.String())
+
+	// so does the rejected one, each request getting its own
+	rec = serve(e, http.MethodGet, "/reports", apiKey)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	var body handler.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, rec.Header().Get(echo.HeaderXRequestID), body.Error.RequestID)
+	require.NotEqual(t, firstID, body.Error.RequestID)
+
+	// without RequestID the client's ID is echoed back
+	e = echo.New()
+	e.GET("/reports", ok, RateLimit(RateLimitConfig{Limiter: limiter}))
+
+	rec = serve(e, http.MethodGet, "/reports", apiKey)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "client-1", body.Error.RequestID)
+}
+
+func TestRateLimitPerRoute(t *testing.T) {
+	e := echo.New()
+	g := e.Group("/api", RateLimit(RateLimitConfig{
+		Limiter:  ratelimit.NewTokenBucket(1, time.Hour, 1),
+		PerRoute: true,
+		Skipper:  func(c echo.Context) bool { return c.Path() == "/api/health" },
+	}))
+	g.GET("/reports", ok)
+	g.GET("/exports", ok)
+	g.GET("/health", ok)
+
+	require.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/api/reports", nil).Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(e, http.MethodGet, "/api/reports", nil).Code)
+	require.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/api/exports", nil).Code)
+	require.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/api/health", nil).Code)
+	require.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/api/health", nil).Code)
+}
+
+func TestKeyExtractors(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	require.Equal(t, "ip:10.0.0.1", DefaultKeyExtractor(c))
+
+	c.Set("user", map[string]interface{}{"id": "user-1"})
+	require.Equal(t, "user:user-1", DefaultKeyExtractor(c))
+
+	req.Header.Set("API-KEY", "key-1")
+	require.Equal(t, "api-key:key-1", DefaultKeyExtractor(c))
+}
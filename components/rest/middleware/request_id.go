@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/vanclief/compose/components/logger"
+)
+
+// RequestID gives every request an ID, sent back in the X-Request-ID header and attached
+// to the request context as the request_id log field. Set it before the other middlewares
+// so their logs and errors carry the ID, and build requests with requests.NewFromContext
+// so handlers reuse it:
+//
+//	e.Use(middleware.RequestID())
+//	api := e.Group("/api", middleware.RateLimit(cfg))
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := uuid.New().String()
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			ctx := logger.WithFields(c.Request().Context(), "request_id", id)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
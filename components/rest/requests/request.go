@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vanclief/compose/components/logger"
 	"github.com/vanclief/compose/types"
 )
 
@@ -48,16 +49,33 @@ func WithTimeout(timeout time.Duration) Option {
 }
 
 func New(header http.Header, ip string, opts ...Option) *StandardRequest {
-	id := uuid.New().String()
-
-	ctx := context.WithValue(context.Background(), "request-id", id)
+	return NewFromContext(context.Background(), header, ip, opts...)
+}
+
+// NewFromContext is like New with a request context carrying the values of ctx, e.g. the
+// echo request's, but not its cancellation. The request_id field of ctx, set by
+// middleware.RequestID, is reused as the request ID so the logs and errors of middlewares
+// and handlers share it.
+func NewFromContext(ctx context.Context, header http.Header, ip string, opts ...Option) *StandardRequest {
+	client := header.Get("Client")
+	ctx = context.WithoutCancel(ctx)
+
+	// loggers obtained with logger.FromContext tag their events with the request
+	v, _ := logger.ContextField(ctx, "request_id")
+	id, _ := v.(string)
+	if id == "" {
+		id = uuid.New().String()
+		ctx = logger.WithFields(ctx, "request_id", id)
+	}
+	ctx = context.WithValue(ctx, "request-id", id)
+	ctx = logger.WithFields(ctx, "request_client", client, "request_ip", ip)
 	ctx, cancel := context.WithTimeout(ctx, DEFAULT_TIMEOUT)
 
 	locale, _ := types.NewLocaleString(header.Get("Accept-Language"))
 
 	request := &StandardRequest{
 		ID:        id,
-		Client:    header.Get("Client"),
+		Client:    client,
 		IP:        ip,
 		Header:    header,
 		Context:   ctx,
@@ -1,10 +1,12 @@
 package requests
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger"
 )
 
 func TestNewNegotiatesLocale(t *testing.T) {
@@ -20,3 +22,31 @@ func TestNewNegotiatesLocale(t *testing.T) {
 	request.SetLocale("es-MX")
 	require.Equal(t, "es-MX", request.GetLocale())
 }
+
+func TestNewSeedsLogFields(t *testing.T) {
+	header := http.Header{}
+	header.Set("Client", "web")
+
+	request := New(header, "127.0.0.1")
+	require.Equal(t, []any{
+		"request_id", request.GetID(),
+		"request_client", "web",
+		"request_ip", "127.0.0.1",
+	}, logger.ContextFields(request.GetContext()))
+}
+
+func TestNewFromContextReusesRequestID(t *testing.T) {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), "request_id", "req-1"))
+	cancel()
+
+	request := NewFromContext(ctx, http.Header{}, "127.0.0.1")
+	require.Equal(t, "req-1", request.GetID())
+	require.Equal(t, []any{
+		"request_id", "req-1",
+		"request_client", "",
+		"request_ip", "127.0.0.1",
+	}, logger.ContextFields(request.GetContext()))
+
+	// the request outlives the connection it came from
+	require.NoError(t, request.GetContext().Err())
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog"
+	restmiddleware "github.com/vanclief/compose/components/rest/middleware"
 	"github.com/ziflex/lecho/v3"
 )
 
@@ -20,6 +21,7 @@ func Start(e *echo.Echo, log zerolog.Logger, port string) {
 	e.Logger = logger
 
 	// Middlewares
+	e.Use(restmiddleware.RequestID())
 	e.Use(lecho.Middleware(lecho.Config{
 		Logger: logger,
 	}))
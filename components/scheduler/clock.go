@@ -0,0 +1,47 @@
+package scheduler
+
+import "time"
+
+// Clock is the source of time used by the Scheduler. It exists so tests can
+// drive the scheduler deterministically, see the schedulertest package.
+// Job and lease contexts still use real deadlines.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the subset of *time.Timer used by the Scheduler.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// Ticker is the subset of *time.Ticker used by the Scheduler.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// realClock implements Clock with the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                   { return time.Now() }
+func (realClock) NewTimer(d time.Duration) Timer   { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type (
+	realTimer  struct{ t *time.Timer }
+	realTicker struct{ t *time.Ticker }
+)
+
+func (t realTimer) C() <-chan time.Time  { return t.t.C }
+func (t realTimer) Stop() bool           { return t.t.Stop() }
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()               { t.t.Stop() }
+
+var (
+	_ Clock  = realClock{}
+	_ Timer  = realTimer{}
+	_ Ticker = realTicker{}
+)
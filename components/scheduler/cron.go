@@ -0,0 +1,365 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+// maxCronSearchDays bounds the search for the next fire time, so expressions
+// that can never match (e.g. "0 0 30 2 *") return a zero time instead of looping forever.
+const maxCronSearchDays = 5 * 366
+
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	cronDayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// cronField describes the valid range and aliases of a single cron field.
+type cronField struct {
+	name      string
+	min, max  int
+	names     map[string]int
+	zeroIsMax bool // 0 ending a range means max, e.g. MON-SUN with Sunday as 0 or 7
+}
+
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: cronMonthNames}
+	dowField    = cronField{name: "day of week", min: 0, max: 7, names: cronDayNames, zeroIsMax: true}
+)
+
+// CronSchedule is a parsed cron expression.
+// Fields are stored as bitsets where bit n is set if value n matches.
+type CronSchedule struct {
+	expr     string
+	seconds  uint64
+	minutes  uint64
+	hours    uint64
+	dom      uint64
+	months   uint64
+	dow      uint64
+	domStar  bool // day of month was "*" or "?"
+	dowStar  bool // day of week was "*" or "?"
+	location *time.Location
+}
+
+// ParseCron parses a standard 5-field cron expression (minute hour dom month dow)
+// or a 6-field expression with a leading seconds field.
+// Fields accept "*", "?", lists, ranges, steps and JAN-DEC / SUN-SAT names; Sunday is 0 or 7,
+// so day of week ranges may end on it (e.g. MON-SUN).
+// The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported,
+// and the expression may be prefixed with "CRON_TZ=<IANA zone>" or "TZ=<IANA zone>".
+func ParseCron(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if spec == "" {
+		return nil, ez.New(ez.EINVALID, "cron expression cannot be empty", nil)
+	}
+
+	var loc *time.Location
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i == -1 {
+			errMsg := fmt.Sprintf("invalid cron expression %q: missing fields after timezone", expr)
+			return nil, ez.New(ez.EINVALID, errMsg, nil)
+		}
+
+		tz := spec[strings.Index(spec, "=")+1 : i]
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			errMsg := fmt.Sprintf("invalid cron expression %q: unknown timezone %q", expr, tz)
+			return nil, ez.New(ez.EINVALID, errMsg, err)
+		}
+		loc = l
+		spec = strings.TrimSpace(spec[i:])
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		d, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			errMsg := fmt.Sprintf("invalid cron expression %q: unknown descriptor %s", expr, spec)
+			return nil, ez.New(ez.EINVALID, errMsg, nil)
+		}
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		errMsg := fmt.Sprintf("invalid cron expression %q: expected 5 or 6 fields, got %d", expr, len(fields))
+		return nil, ez.New(ez.EINVALID, errMsg, nil)
+	}
+
+	cs := &CronSchedule{expr: expr, location: loc}
+	var err error
+
+	if cs.seconds, _, err = parseCronField(fields[0], secondField); err != nil {
+		return nil, ez.Wrap(err)
+	}
+	if cs.minutes, _, err = parseCronField(fields[1], minuteField); err != nil {
+		return nil, ez.Wrap(err)
+	}
+	if cs.hours, _, err = parseCronField(fields[2], hourField); err != nil {
+		return nil, ez.Wrap(err)
+	}
+	if cs.dom, cs.domStar, err = parseCronField(fields[3], domField); err != nil {
+		return nil, ez.Wrap(err)
+	}
+	if cs.months, _, err = parseCronField(fields[4], monthField); err != nil {
+		return nil, ez.Wrap(err)
+	}
+	if cs.dow, cs.dowStar, err = parseCronField(fields[5], dowField); err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	// 7 is an alias for Sunday
+	if cs.dow&(1<<7) != 0 {
+		cs.dow = (cs.dow &^ (1 << 7)) | 1
+	}
+
+	return cs, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Location returns the timezone embedded in the expression via CRON_TZ, or nil if none.
+func (c *CronSchedule) Location() *time.Location {
+	return c.location
+}
+
+// Next returns the first fire time strictly after t, or the zero time if the
+// expression has no match within the next five years.
+// Fire times are computed on the wall clock of the schedule's location (or t's
+// location if none was set). Wall times skipped by a DST jump fire at the first
+// instant after the gap, and wall times repeated when clocks go back fire once.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := c.location
+	if loc == nil {
+		loc = t.Location()
+	}
+	return c.nextIn(t, loc)
+}
+
+// nextIn returns the first fire time strictly after t on the wall clock of loc.
+func (c *CronSchedule) nextIn(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+
+	y, m, d := t.Date()
+	for i := 0; i < maxCronSearchDays; i++ {
+		// noon is always a valid wall time, so the date never shifts
+		day := time.Date(y, m, d+i, 12, 0, 0, 0, loc)
+		if !c.matchDay(day) {
+			continue
+		}
+		if next, ok := c.nextInDay(day, t); ok {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay reports whether day satisfies the month, day-of-month and day-of-week fields.
+// Like standard cron, when both day fields are restricted a day matching either is accepted.
+func (c *CronSchedule) matchDay(day time.Time) bool {
+	if !hasBit(c.months, int(day.Month())) {
+		return false
+	}
+
+	domMatch := hasBit(c.dom, day.Day())
+	dowMatch := hasBit(c.dow, int(day.Weekday()))
+
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowMatch
+	case c.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// nextInDay returns the earliest matching time within day that is after t.
+func (c *CronSchedule) nextInDay(day, t time.Time) (time.Time, bool) {
+	y, m, d := day.Date()
+	loc := day.Location()
+
+	var best time.Time
+	for h := 0; h < 24; h++ {
+		if !hasBit(c.hours, h) {
+			continue
+		}
+		if !best.IsZero() && !cronTime(y, m, d, h, 0, 0, loc).Before(best) {
+			break
+		}
+
+		for mi := 0; mi < 60; mi++ {
+			if !hasBit(c.minutes, mi) {
+				continue
+			}
+			if !best.IsZero() && !cronTime(y, m, d, h, mi, 0, loc).Before(best) {
+				break
+			}
+			// skip whole minutes that end before t
+			if !cronTime(y, m, d, h, mi, 59, loc).After(t) {
+				continue
+			}
+
+			for s := 0; s < 60; s++ {
+				if !hasBit(c.seconds, s) {
+					continue
+				}
+				candidate := cronTime(y, m, d, h, mi, s, loc)
+				if candidate.After(t) {
+					if best.IsZero() || candidate.Before(best) {
+						best = candidate
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return best, !best.IsZero()
+}
+
+// cronTime returns the instant of the given wall time in loc.
+// Wall times that don't exist because of a DST jump resolve to the end of the gap.
+func cronTime(y int, m time.Month, d, h, mi, s int, loc *time.Location) time.Time {
+	t := time.Date(y, m, d, h, mi, s, 0, loc)
+	if t.Hour() != h || t.Minute() != mi || t.Second() != s {
+		// time.Date normalized into the zone before the gap, so jump to the transition
+		_, end := t.ZoneBounds()
+		if !end.IsZero() {
+			return end
+		}
+	}
+	return t
+}
+
+// parseCronField parses a single comma separated cron field into a bitset.
+// The returned bool is true if the field was an unrestricted "*" or "?".
+func parseCronField(field string, f cronField) (uint64, bool, error) {
+	if field == "*" || field == "?" {
+		return bitRange(f.min, f.max, 1), true, nil
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		b, err := parseCronPart(part, f)
+		if err != nil {
+			return 0, false, ez.Wrap(err)
+		}
+		bits |= b
+	}
+
+	return bits, false, nil
+}
+
+// parseCronPart parses a single list element: "*", "a", "a-b", optionally followed by "/step".
+func parseCronPart(part string, f cronField) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			errMsg := fmt.Sprintf("invalid step %q in %s field", stepPart, f.name)
+			return 0, ez.New(ez.EINVALID, errMsg, nil)
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		lo, hi = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		loStr, hiStr, _ := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = parseCronValue(loStr, f); err != nil {
+			return 0, ez.Wrap(err)
+		}
+		if hi, err = parseCronValue(hiStr, f); err != nil {
+			return 0, ez.Wrap(err)
+		}
+		if f.zeroIsMax && hi == 0 && lo > 0 {
+			hi = f.max
+		}
+		if lo > hi {
+			errMsg := fmt.Sprintf("invalid range %q in %s field", rangePart, f.name)
+			return 0, ez.New(ez.EINVALID, errMsg, nil)
+		}
+	default:
+		v, err := parseCronValue(rangePart, f)
+		if err != nil {
+			return 0, ez.Wrap(err)
+		}
+		lo, hi = v, v
+		// "a/n" means every n starting at a
+		if hasStep {
+			hi = f.max
+		}
+	}
+
+	return bitRange(lo, hi, step), nil
+}
+
+// parseCronValue parses a single number or name and checks it against the field bounds.
+func parseCronValue(s string, f cronField) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		errMsg := fmt.Sprintf("invalid value %q in %s field", s, f.name)
+		return 0, ez.New(ez.EINVALID, errMsg, nil)
+	}
+	if v < f.min || v > f.max {
+		errMsg := fmt.Sprintf("value %d out of range [%d-%d] in %s field", v, f.min, f.max, f.name)
+		return 0, ez.New(ez.EINVALID, errMsg, nil)
+	}
+
+	return v, nil
+}
+
+func bitRange(lo, hi, step int) uint64 {
+	var bits uint64
+	for i := lo; i <= hi; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits
+}
+
+func hasBit(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "0 3 * *"},
+		{"too many fields", "0 0 3 * * MON 2024"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"bad name", "0 0 * * FUNDAY"},
+		{"inverted range", "0 5-3 * * *"},
+		{"wrapping weekday range", "0 0 * * FRI-MON"},
+		{"zero step", "*/0 * * * *"},
+		{"unknown descriptor", "@fortnightly"},
+		{"unknown timezone", "CRON_TZ=Mars/Olympus 0 3 * * *"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCron(tc.expr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2024, time.January, 10, 10, 15, 30, 0, time.UTC) // Wednesday
+
+	testCases := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2024, 1, 10, 10, 16, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)},
+		{"daily at 3am", "0 3 * * *", time.Date(2024, 1, 11, 3, 0, 0, 0, time.UTC)},
+		{"weekly on monday", "0 3 * * MON", time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"first of month", "@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"range and list", "0 9-17/4 * * MON-FRI", time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC)},
+		{"range ending sunday", "0 0 * * SAT-SUN", time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"month names", "0 0 1 MAR,JUN *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"dom or dow", "0 0 20 * SAT", time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"with seconds", "*/20 * * * * *", time.Date(2024, 1, 10, 10, 15, 40, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseCron(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseCronSundayRange(t *testing.T) {
+	everyDay := bitRange(0, 6, 1)
+
+	testCases := []struct {
+		expr string
+		dow  uint64
+	}{
+		{"0 0 * * MON-SUN", everyDay},
+		{"0 0 * * 1-7", everyDay},
+		{"0 0 * * 1-0", everyDay},
+		{"0 0 * * SUN-SAT", everyDay},
+		{"0 0 * * FRI-SUN", bitRange(5, 6, 1) | 1},
+		{"0 0 * * MON-SUN/2", bitRange(1, 5, 2) | 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.dow, schedule.dow)
+			require.False(t, schedule.dowStar)
+		})
+	}
+}
+
+func TestCronScheduleNextNeverMatches(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestCronScheduleNextTimezone(t *testing.T) {
+	schedule, err := ParseCron("CRON_TZ=America/Mexico_City 0 3 * * *")
+	require.NoError(t, err)
+
+	from := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+	next := schedule.Next(from)
+
+	// 03:00 in Mexico City (UTC-6) is 09:00 UTC
+	require.Equal(t, time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestCronScheduleNextAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("skipped wall time fires after the gap", func(t *testing.T) {
+		schedule, err := ParseCron("30 2 * * *")
+		require.NoError(t, err)
+
+		// 2024-03-10 02:00 EST jumps to 03:00 EDT
+		from := time.Date(2024, time.March, 9, 12, 0, 0, 0, loc)
+		next := schedule.nextIn(from, loc)
+		require.Equal(t, time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC), next.UTC())
+
+		next = schedule.nextIn(next, loc)
+		require.Equal(t, time.Date(2024, 3, 11, 6, 30, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("repeated wall time fires once", func(t *testing.T) {
+		schedule, err := ParseCron("30 1 * * *")
+		require.NoError(t, err)
+
+		// 2024-11-03 02:00 EDT goes back to 01:00 EST
+		from := time.Date(2024, time.November, 2, 12, 0, 0, 0, loc)
+		first := schedule.nextIn(from, loc)
+		require.Equal(t, 3, first.Day())
+
+		second := schedule.nextIn(first, loc)
+		require.Equal(t, 4, second.Day())
+	})
+
+	t.Run("hourly keeps a one hour cadence in UTC", func(t *testing.T) {
+		schedule, err := ParseCron("0 * * * *")
+		require.NoError(t, err)
+
+		next := time.Date(2024, time.March, 10, 0, 30, 0, 0, loc)
+		for i := 0; i < 4; i++ {
+			prev := next
+			next = schedule.nextIn(prev, loc)
+			require.True(t, next.After(prev))
+			require.LessOrEqual(t, next.Sub(prev), time.Hour)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import "time"
+
+// A cycle is the period slot offsets are measured in: the hour for ticks of up to an hour,
+// and the day, from local midnight, for longer ticks. Ticks must divide their cycle evenly.
+const (
+	hourCycle = time.Hour
+	dayCycle  = 24 * time.Hour
+)
+
+// cycleFor returns the cycle a tick repeats in.
+func cycleFor(tick time.Duration) time.Duration {
+	if tick > hourCycle {
+		return dayCycle
+	}
+	return hourCycle
+}
+
+// cycleOffset returns how far t is into its cycle, using wall-clock time for day cycles.
+func (s *Scheduler) cycleOffset(t time.Time) time.Duration {
+	off := time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+	if s.cycle == dayCycle {
+		off += time.Duration(t.Hour()) * time.Hour
+	}
+	return off
+}
+
+// atOffset returns the time off into the cycle containing t. Offsets past the end
+// of the cycle roll over into the next one.
+func (s *Scheduler) atOffset(t time.Time, off time.Duration) time.Time {
+	if s.cycle == hourCycle {
+		// absolute arithmetic keeps repeated wall-clock hours (DST) apart
+		return t.Add(off - s.cycleOffset(t))
+	}
+
+	h := int(off / time.Hour)
+	m := int(off % time.Hour / time.Minute)
+	sec := int(off % time.Minute / time.Second)
+	return time.Date(t.Year(), t.Month(), t.Day(), h, m, sec, 0, t.Location())
+}
+
+// slotOf returns the slot offset of the tick boundary at or before t.
+func (s *Scheduler) slotOf(t time.Time) time.Duration {
+	return s.cycleOffset(t) / s.tick * s.tick
+}
+
+// validSlot reports whether off is a tick boundary within the cycle.
+func (s *Scheduler) validSlot(off time.Duration) bool {
+	return off >= 0 && off < s.cycle && off%s.tick == 0
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func noopJob(context.Context) {}
+
+func TestNewTick(t *testing.T) {
+	testCases := []struct {
+		name  string
+		tick  time.Duration
+		valid bool
+	}{
+		{"30 seconds", 30 * time.Second, true},
+		{"1 second", time.Second, true},
+		{"15 minutes", 15 * time.Minute, true},
+		{"1 hour", time.Hour, true},
+		{"2 hours", 2 * time.Hour, true},
+		{"6 hours", 6 * time.Hour, true},
+		{"1 day", 24 * time.Hour, true},
+		{"zero", 0, false},
+		{"sub second", 1500 * time.Millisecond, false},
+		{"7 minutes", 7 * time.Minute, false},
+		{"5 hours", 5 * time.Hour, false},
+		{"25 hours", 25 * time.Hour, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.tick)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestAddSlots(t *testing.T) {
+	s, err := New(30 * time.Second)
+	require.NoError(t, err)
+	require.NoError(t, s.Add("minute", 5, noopJob))
+	require.NoError(t, s.AddAt("half", 30*time.Second, noopJob))
+	require.Error(t, s.AddAt("offbeat", 45*time.Second, noopJob))
+	require.Error(t, s.AddAt("too late", time.Hour, noopJob))
+
+	s, err = New(6 * time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, s.Add("morning", 6*60, noopJob))
+	require.NoError(t, s.AddAt("evening", 18*time.Hour, noopJob))
+	require.Error(t, s.Add("hourly", 60, noopJob))
+}
+
+func TestNextAligned(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		tick     time.Duration
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			"30 seconds",
+			30 * time.Second,
+			time.Date(2024, 1, 10, 10, 7, 12, 0, time.UTC),
+			time.Date(2024, 1, 10, 10, 7, 30, 0, time.UTC),
+		},
+		{
+			"30 seconds rolls over the hour",
+			30 * time.Second,
+			time.Date(2024, 1, 10, 10, 59, 30, 0, time.UTC),
+			time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			"2 hours",
+			2 * time.Hour,
+			time.Date(2024, 1, 10, 9, 15, 0, 0, time.UTC),
+			time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			"6 hours rolls over the day",
+			6 * time.Hour,
+			time.Date(2024, 1, 10, 19, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"6 hours follows the wall clock after DST",
+			6 * time.Hour,
+			time.Date(2024, 3, 10, 1, 0, 0, 0, loc),
+			time.Date(2024, 3, 10, 6, 0, 0, 0, loc),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := New(tc.tick)
+			require.NoError(t, err)
+			require.True(t, tc.expected.Equal(s.nextAligned(tc.now)))
+		})
+	}
+}
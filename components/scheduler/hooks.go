@@ -0,0 +1,59 @@
+package scheduler
+
+import "context"
+
+// Hooks are callbacks invoked around every job run, in the job's goroutine.
+// Any of them may be nil. They should return quickly since they delay the job's completion.
+type Hooks struct {
+	// OnStart is called once the run holds its lease, right before the job is invoked.
+	OnStart func(ctx context.Context, run Run)
+	// OnFinish is called after every started run, whatever its outcome.
+	OnFinish func(ctx context.Context, run Run)
+	// OnError is called when a run fails or times out, after its retries are exhausted.
+	OnError func(ctx context.Context, run Run, err error)
+	// OnPanic is called when a run panics, with the recovered value and its stack trace.
+	OnPanic func(ctx context.Context, run Run, recovered any, stack []byte)
+}
+
+// WithHooks registers hooks invoked around every job run.
+// It can be given multiple times; hooks are called in registration order.
+func WithHooks(h Hooks) Option {
+	return func(s *Scheduler) {
+		s.hooks = append(s.hooks, h)
+	}
+}
+
+// OnStart registers fn to be called when a job run starts, see Hooks.
+func OnStart(fn func(ctx context.Context, run Run)) Option {
+	return WithHooks(Hooks{OnStart: fn})
+}
+
+// OnFinish registers fn to be called when a job run ends, see Hooks.
+func OnFinish(fn func(ctx context.Context, run Run)) Option {
+	return WithHooks(Hooks{OnFinish: fn})
+}
+
+// OnError registers fn to be called when a job run fails, see Hooks.
+func OnError(fn func(ctx context.Context, run Run, err error)) Option {
+	return WithHooks(Hooks{OnError: fn})
+}
+
+// OnPanic registers fn to be called when a job run panics, see Hooks.
+func OnPanic(fn func(ctx context.Context, run Run, recovered any, stack []byte)) Option {
+	return WithHooks(Hooks{OnPanic: fn})
+}
+
+// callHooks invokes call for every registered Hooks, recovering from panics
+// so a faulty hook can't take down the scheduler.
+func (s *Scheduler) callHooks(id string, call func(h Hooks)) {
+	for _, h := range s.hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					s.log.Error().Str("job_id", id).Any("panic", r).Msg("Scheduler hook panic")
+				}
+			}()
+			call(h)
+		}()
+	}
+}
@@ -0,0 +1,83 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger/logtest"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/ez"
+)
+
+func TestHooks(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var panicStack []byte
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+
+	log := logtest.New()
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC),
+		scheduler.WithLogger(log),
+		scheduler.OnStart(func(ctx context.Context, run scheduler.Run) {
+			record("start:" + run.JobID)
+		}),
+		scheduler.OnFinish(func(ctx context.Context, run scheduler.Run) {
+			record("finish:" + run.JobID + ":" + string(run.Status))
+		}),
+		scheduler.OnError(func(ctx context.Context, run scheduler.Run, err error) {
+			record("error:" + run.JobID + ":" + ez.ErrorMessage(err))
+		}),
+		scheduler.OnPanic(func(ctx context.Context, run scheduler.Run, recovered any, stack []byte) {
+			mu.Lock()
+			panicStack = stack
+			mu.Unlock()
+			record("panic:" + run.JobID + ":" + recovered.(string))
+		}),
+		// a faulty hook must not affect the job or the other hooks
+		scheduler.OnFinish(func(ctx context.Context, run scheduler.Run) {
+			panic("bad hook")
+		}),
+	)
+
+	ctx := context.Background()
+	require.True(t, h.s.RunOnce(ctx, "ok", noop))
+	_, ok := h.recorder.WaitRuns(1, waitTimeout)
+	require.True(t, ok)
+
+	require.True(t, h.s.RunOnceErr(ctx, "fails", func(ctx context.Context) error {
+		return ez.New(ez.EINTERNAL, "boom", nil)
+	}))
+	_, ok = h.recorder.WaitRuns(2, waitTimeout)
+	require.True(t, ok)
+
+	require.True(t, h.s.RunOnce(ctx, "panics", func(ctx context.Context) {
+		panic("kaboom")
+	}))
+	runs, ok := h.recorder.WaitRuns(3, waitTimeout)
+	require.True(t, ok)
+	require.Equal(t, scheduler.RunStatusPanic, runs[2].Status)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{
+		"start:ok",
+		"finish:ok:ok",
+		"start:fails",
+		"error:fails:boom",
+		"finish:fails:error",
+		"start:panics",
+		"panic:panics:kaboom",
+		"finish:panics:panic",
+	}, calls)
+	require.NotEmpty(t, panicStack)
+
+	require.True(t, log.HasEntry(logtest.LevelError, "Scheduler job panic", "job_id", "panics", "panic", "kaboom"), log)
+	require.Len(t, log.Find(logtest.LevelError, "Scheduler hook panic", "panic", "bad hook"), 3, log)
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// JitterMode decides how a job's jitter offset is picked.
+type JitterMode string
+
+const (
+	// JitterRandom picks a new random offset on every fire. This is the default.
+	JitterRandom JitterMode = "random"
+	// JitterHash derives a stable offset from the job ID, so every job keeps its own
+	// delay across fires and replicas.
+	JitterHash JitterMode = "hash"
+)
+
+// Jitter delays the start of a scheduled job by an offset in [0, Max) after its fire time,
+// spreading jobs that share a slot. The fire time used for leases and run history is unchanged.
+// Max should stay well below the tick, or jittered runs may overlap the next fire.
+type Jitter struct {
+	Max  time.Duration
+	Mode JitterMode
+}
+
+// offset returns the delay for a run of the job id.
+func (j Jitter) offset(id string) time.Duration {
+	if j.Max <= 0 {
+		return 0
+	}
+	if j.Mode == JitterHash {
+		h := fnv.New64a()
+		h.Write([]byte(id))
+		return time.Duration(h.Sum64() % uint64(j.Max))
+	}
+	return rand.N(j.Max)
+}
+
+// jitterFor returns the delay for a run of the job id, from its JobJitter or the scheduler's WithJitter.
+func (s *Scheduler) jitterFor(id string, opts *jobOptions) time.Duration {
+	j := s.jitter
+	if opts.jitter != nil {
+		j = *opts.jitter
+	}
+	return j.offset(id)
+}
+
+// dispatch spawns a scheduled job for fireTime once its jitter delay has passed, unless
+// the job was paused or removed in the meantime.
+func (s *Scheduler) dispatch(ctx context.Context, id string, job ErrorJob, fireTime time.Time, opts *jobOptions) {
+	jitter := s.jitterFor(id, opts)
+	if jitter <= 0 {
+		if !s.spawnJob(ctx, id, job, fireTime, opts) {
+			s.log.Debug().Str("job_id", id).Msg("Job already running or invalid, skipping")
+		}
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		timer := s.clock.NewTimer(jitter)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+		}
+
+		// the job may have been paused or removed while waiting
+		s.mu.RLock()
+		scheduled := s.isScheduled(id, opts)
+		s.mu.RUnlock()
+		if !scheduled {
+			s.log.Debug().Str("job_id", id).Msg("Job paused or removed during its jitter, skipping")
+			return
+		}
+
+		run := pendingRun{ctx: ctx, job: job, fireTime: fireTime, opts: opts, jitter: jitter}
+		if !s.spawnRuns(id, []pendingRun{run}) {
+			s.log.Debug().Str("job_id", id).Msg("Job already running or invalid, skipping")
+		}
+	}()
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitterOffset(t *testing.T) {
+	require.Zero(t, Jitter{}.offset("job"))
+
+	hash := Jitter{Max: time.Minute, Mode: JitterHash}
+	first := hash.offset("job")
+	require.Equal(t, first, hash.offset("job"))
+	require.NotEqual(t, first, hash.offset("other job"))
+
+	random := Jitter{Max: time.Second}
+	for i := 0; i < 100; i++ {
+		require.GreaterOrEqual(t, hash.offset("job"), time.Duration(0))
+		require.Less(t, hash.offset("job"), time.Minute)
+		require.GreaterOrEqual(t, random.offset("job"), time.Duration(0))
+		require.Less(t, random.offset("job"), time.Second)
+	}
+}
+
+func TestJitterFor(t *testing.T) {
+	s, err := New(15*time.Minute, WithJitter(Jitter{Max: time.Minute, Mode: JitterHash}))
+	require.NoError(t, err)
+
+	require.Equal(t, s.jitter.offset("job"), s.jitterFor("job", newJobOptions(nil)))
+	require.Zero(t, s.jitterFor("job", newJobOptions([]JobOption{JobJitter(Jitter{})})))
+}
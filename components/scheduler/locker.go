@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Locker hands out cluster-wide leases so that, when several replicas run the
+// same Scheduler, each job fire time runs on exactly one of them.
+// Implementations MUST be safe for concurrent use.
+type Locker interface {
+	// Acquire tries to claim jobID for fireTime, holding the lease for ttl.
+	// It returns ok=false without an error if another replica already claimed
+	// this fire time or still holds the lease from a previous one.
+	Acquire(ctx context.Context, jobID string, fireTime time.Time, ttl time.Duration) (lease Lease, ok bool, err error)
+}
+
+// Lease is a claim on a job returned by a Locker.
+type Lease interface {
+	// Renew extends the lease by ttl. It returns an error if the lease was lost.
+	Renew(ctx context.Context, ttl time.Duration) error
+	// Release gives the lease up so the next fire time can be claimed right away.
+	Release(ctx context.Context) error
+}
@@ -0,0 +1,233 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+// JobKind tells how a job is scheduled.
+type JobKind string
+
+const (
+	JobKindSlot JobKind = "slot"
+	JobKindCron JobKind = "cron"
+)
+
+// JobInfo describes a registered job.
+type JobInfo struct {
+	ID       string          `json:"id"`
+	Kind     JobKind         `json:"kind"`
+	Slots    []time.Duration `json:"slots,omitempty"`    // offsets within the cycle, for slot jobs
+	Cron     string          `json:"cron,omitempty"`     // cron expression, for cron jobs
+	Timezone string          `json:"timezone,omitempty"` // evaluation timezone, for cron jobs
+	NextRun  time.Time       `json:"next_run"`           // zero if the job never fires again
+	Paused   bool            `json:"paused"`
+	Running  bool            `json:"running"`
+}
+
+// Jobs returns every registered job sorted by ID.
+func (s *Scheduler) Jobs() []JobInfo {
+	now := s.clock.Now()
+
+	s.runMu.Lock()
+	running := make(map[string]bool, len(s.running))
+	for id := range s.running {
+		running[id] = true
+	}
+	s.runMu.Unlock()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	slotsByID := make(map[string][]time.Duration)
+	for slot, sjs := range s.slots {
+		for _, sj := range sjs {
+			slotsByID[sj.id] = append(slotsByID[sj.id], slot)
+		}
+	}
+
+	jobs := make([]JobInfo, 0, len(slotsByID)+len(s.crons))
+	for id, slots := range slotsByID {
+		slices.Sort(slots)
+		_, paused := s.paused[id]
+		jobs = append(jobs, JobInfo{
+			ID:      id,
+			Kind:    JobKindSlot,
+			Slots:   slots,
+			NextRun: s.nextSlotRun(slots, now),
+			Paused:  paused,
+			Running: running[id],
+		})
+	}
+
+	for id, cj := range s.crons {
+		_, paused := s.paused[id]
+		jobs = append(jobs, JobInfo{
+			ID:       id,
+			Kind:     JobKindCron,
+			Cron:     cj.schedule.String(),
+			Timezone: cj.location.String(),
+			NextRun:  cj.nextAfter(now),
+			Paused:   paused,
+			Running:  running[id],
+		})
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
+
+// Job returns the registered job with the given id.
+func (s *Scheduler) Job(id string) (JobInfo, error) {
+	for _, info := range s.Jobs() {
+		if info.ID == id {
+			return info, nil
+		}
+	}
+	return JobInfo{}, jobNotFound(id)
+}
+
+// Remove unregisters a job from every slot or its cron schedule.
+// A run already in progress is not interrupted, one still waiting out its jitter is dropped.
+func (s *Scheduler) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := false
+	if _, ok := s.crons[id]; ok {
+		delete(s.crons, id)
+		found = true
+	}
+	for slot, sjs := range s.slots {
+		filtered := slices.DeleteFunc(sjs, func(sj scheduledJob) bool { return sj.id == id })
+		if len(filtered) != len(sjs) {
+			found = true
+		}
+		s.slots[slot] = filtered
+	}
+	if !found {
+		return jobNotFound(id)
+	}
+
+	delete(s.paused, id)
+	s.wakeCron()
+	return nil
+}
+
+// Pause stops a job from firing until Resume is called. A run already in progress is not interrupted,
+// one still waiting out its jitter is dropped.
+func (s *Scheduler) Pause(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hasJob(id) {
+		return jobNotFound(id)
+	}
+	s.paused[id] = struct{}{}
+	return nil
+}
+
+// Resume lets a paused job fire again from its next fire time. Missed fire times are not replayed.
+func (s *Scheduler) Resume(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hasJob(id) {
+		return jobNotFound(id)
+	}
+	delete(s.paused, id)
+	return nil
+}
+
+// Trigger runs a registered job right away with its own options, even if it is paused.
+// The run is bound to the context given to Start, or to a background context before Start.
+// Returns false if the job is already running and its OverlapPolicy skipped the run.
+func (s *Scheduler) Trigger(id string) (bool, error) {
+	s.mu.RLock()
+	job, opts, ok := s.findJob(id)
+	ctx := s.startCtx
+	s.mu.RUnlock()
+
+	if !ok {
+		return false, jobNotFound(id)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return s.spawnJob(ctx, id, job, s.clock.Now(), opts), nil
+}
+
+// nextSlotRun returns the first tick boundary after now that falls on one of slots.
+func (s *Scheduler) nextSlotRun(slots []time.Duration, now time.Time) time.Time {
+	t := s.nextAligned(now)
+	for i := time.Duration(0); i < s.cycle/s.tick; i++ {
+		if slices.Contains(slots, s.cycleOffset(t)) {
+			return t
+		}
+		t = s.nextAligned(t)
+	}
+	return time.Time{}
+}
+
+// findJob returns the job registered with id. The caller must hold mu.
+func (s *Scheduler) findJob(id string) (ErrorJob, *jobOptions, bool) {
+	if cj, ok := s.crons[id]; ok {
+		return cj.job, cj.opts, true
+	}
+	for _, sjs := range s.slots {
+		for _, sj := range sjs {
+			if sj.id == id {
+				return sj.job, sj.opts, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// hasJob reports whether id is registered. The caller must hold mu.
+func (s *Scheduler) hasJob(id string) bool {
+	_, _, ok := s.findJob(id)
+	return ok
+}
+
+// hasSlotJob reports whether id is registered on any slot. The caller must hold mu.
+func (s *Scheduler) hasSlotJob(id string) bool {
+	for _, sjs := range s.slots {
+		for _, sj := range sjs {
+			if sj.id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isScheduled reports whether the registration of id with opts is still there and not
+// paused, opts telling it apart from a job registered again with the same id. The caller must hold mu.
+func (s *Scheduler) isScheduled(id string, opts *jobOptions) bool {
+	if _, paused := s.paused[id]; paused {
+		return false
+	}
+	if cj, ok := s.crons[id]; ok && cj.opts == opts {
+		return true
+	}
+	for _, sjs := range s.slots {
+		for _, sj := range sjs {
+			if sj.id == id && sj.opts == opts {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jobNotFound(id string) error {
+	errMsg := fmt.Sprintf("job %s not found", id)
+	return ez.New(ez.ENOTFOUND, errMsg, nil)
+}
@@ -0,0 +1,87 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/ez"
+)
+
+func TestJobs(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 10, 20, 0, 0, time.UTC))
+	require.NoError(t, h.s.AddMany("half", []int{30, 0}, noop))
+	require.NoError(t, h.s.AddCron("nightly", "0 3 * * *", noop, scheduler.JobTimezone("UTC")))
+
+	require.Equal(t, []scheduler.JobInfo{
+		{
+			ID:       "half",
+			Kind:     scheduler.JobKindSlot,
+			Slots:    []time.Duration{0, 30 * time.Minute},
+			NextRun:  time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC),
+			Paused:   false,
+			Running:  false,
+			Cron:     "",
+			Timezone: "",
+		},
+		{
+			ID:       "nightly",
+			Kind:     scheduler.JobKindCron,
+			Cron:     "0 3 * * *",
+			Timezone: "UTC",
+			NextRun:  time.Date(2024, 1, 11, 3, 0, 0, 0, time.UTC),
+		},
+	}, h.s.Jobs())
+
+	err := h.s.AddCron("half", "* * * * *", noop)
+	require.Equal(t, ez.ECONFLICT, ez.ErrorCode(err))
+}
+
+func TestPauseResumeRemove(t *testing.T) {
+	h := newHarness(t, time.Hour, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC))
+	require.NoError(t, h.s.Add("paused", 0, noop))
+	require.NoError(t, h.s.Add("removed", 0, noop))
+	require.NoError(t, h.s.Add("active", 0, noop))
+
+	require.NoError(t, h.s.Pause("paused"))
+	require.NoError(t, h.s.Remove("removed"))
+	require.Equal(t, ez.ENOTFOUND, ez.ErrorCode(h.s.Pause("removed")))
+	require.Equal(t, ez.ENOTFOUND, ez.ErrorCode(h.s.Remove("missing")))
+
+	info, err := h.s.Job("paused")
+	require.NoError(t, err)
+	require.True(t, info.Paused)
+
+	h.start(t)
+	h.clock.Set(time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC))
+	_, ok := h.recorder.WaitRuns(1, waitTimeout)
+	require.True(t, ok)
+	h.clock.BlockUntil(1)
+
+	require.NoError(t, h.s.Resume("paused"))
+	h.clock.Advance(time.Hour)
+	_, ok = h.recorder.WaitRuns(3, waitTimeout)
+	require.True(t, ok)
+
+	require.Equal(t, []time.Time{time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)}, h.recorder.FireTimes("paused"))
+	require.Len(t, h.recorder.FireTimes("active"), 2)
+	require.Empty(t, h.recorder.FireTimes("removed"))
+}
+
+func TestTrigger(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC))
+
+	ran := make(chan struct{})
+	require.NoError(t, h.s.Add("export", 0, func(ctx context.Context) { close(ran) }))
+	require.NoError(t, h.s.Pause("export"))
+
+	started, err := h.s.Trigger("export")
+	require.NoError(t, err)
+	require.True(t, started)
+	<-ran
+
+	_, err = h.s.Trigger("missing")
+	require.Equal(t, ez.ENOTFOUND, ez.ErrorCode(err))
+}
@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// MaxMissedRuns caps how many missed fire times MisfireRunAll replays, keeping the most recent ones.
+const MaxMissedRuns = 100
+
+// MisfirePolicy decides what happens to fire times missed while the scheduler was down.
+type MisfirePolicy string
+
+const (
+	// MisfireSkip drops missed runs and waits for the next fire time. This is the default.
+	MisfireSkip MisfirePolicy = "skip"
+	// MisfireRunOnce runs the job once on Start if at least one fire time was missed.
+	MisfireRunOnce MisfirePolicy = "run_once"
+	// MisfireRunAll runs the job on Start once per missed fire time, oldest first.
+	MisfireRunAll MisfirePolicy = "run_all"
+)
+
+// FireStore persists the last fire time of every job, so a restarted Scheduler
+// can detect the runs it missed. Implementations MUST be safe for concurrent use.
+type FireStore interface {
+	// LastFire returns the last fire time of jobID; ok is false if the job never fired.
+	LastFire(ctx context.Context, jobID string) (fireTime time.Time, ok bool, err error)
+	// SetLastFire stores fireTime as the last fire time of jobID. Older times must not overwrite newer ones.
+	SetLastFire(ctx context.Context, jobID string, fireTime time.Time) error
+}
+
+// catchUpJob is a job whose missed fire times must be handled on Start.
+type catchUpJob struct {
+	id     string
+	job    ErrorJob
+	opts   *jobOptions
+	missed func(after, now time.Time) []time.Time
+}
+
+// catchUp dispatches the runs missed since each job's last stored fire time,
+// following the job's MisfirePolicy. Jobs that never fired are left alone.
+func (s *Scheduler) catchUp(ctx context.Context) {
+	if s.fireStore == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	for _, cu := range s.catchUpJobs() {
+		lookupCtx, cancel := context.WithTimeout(ctx, DefaultRecordTimeout)
+		last, ok, err := s.fireStore.LastFire(lookupCtx, cu.id)
+		cancel()
+		if err != nil {
+			s.log.Warn().Str("job_id", cu.id).Err(err).Msg("Scheduler could not load last fire time, skipping catch-up")
+			continue
+		} else if !ok {
+			continue
+		}
+
+		missed := cu.missed(last, now)
+		if len(missed) == 0 {
+			continue
+		}
+
+		s.log.Info().
+			Str("job_id", cu.id).
+			Time("last_fire", last).
+			Int("missed", len(missed)).
+			Str("policy", string(cu.opts.misfire)).
+			Msg("Scheduler job missed fire times")
+
+		switch cu.opts.misfire {
+		case MisfireRunOnce:
+			s.spawnJob(ctx, cu.id, cu.job, missed[len(missed)-1], cu.opts)
+		case MisfireRunAll:
+			s.spawnMissed(ctx, cu, missed)
+		}
+	}
+}
+
+// spawnMissed runs the job once per missed fire time, one after the other.
+func (s *Scheduler) spawnMissed(ctx context.Context, cu catchUpJob, missed []time.Time) {
+	runs := make([]pendingRun, 0, len(missed))
+	for _, fireTime := range missed {
+		runs = append(runs, pendingRun{ctx: ctx, job: cu.job, fireTime: fireTime, opts: cu.opts})
+	}
+	s.spawnRuns(cu.id, runs)
+}
+
+// catchUpJobs returns the registered, unpaused jobs with a policy other than MisfireSkip.
+func (s *Scheduler) catchUpJobs() []catchUpJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var jobs []catchUpJob
+
+	// a slot job id may be registered on several slots
+	slotsByID := make(map[string][]time.Duration)
+	slotJobs := make(map[string]scheduledJob)
+	for slot, sjs := range s.slots {
+		for _, sj := range sjs {
+			if _, paused := s.paused[sj.id]; paused || sj.opts.misfire == "" || sj.opts.misfire == MisfireSkip {
+				continue
+			}
+			slotsByID[sj.id] = append(slotsByID[sj.id], slot)
+			slotJobs[sj.id] = sj
+		}
+	}
+	for id, sj := range slotJobs {
+		slots := slotsByID[id]
+		jobs = append(jobs, catchUpJob{
+			id:   id,
+			job:  sj.job,
+			opts: sj.opts,
+			missed: func(after, now time.Time) []time.Time {
+				return s.missedSlots(slots, after, now)
+			},
+		})
+	}
+
+	for _, cj := range s.crons {
+		if _, paused := s.paused[cj.id]; paused || cj.opts.misfire == "" || cj.opts.misfire == MisfireSkip {
+			continue
+		}
+		jobs = append(jobs, catchUpJob{
+			id:     cj.id,
+			job:    cj.job,
+			opts:   cj.opts,
+			missed: cj.missed,
+		})
+	}
+
+	return jobs
+}
+
+// missedSlots returns the tick boundaries in (after, now] that fall on one of slots, the
+// last MaxMissedRuns of them. Only the cycles that can hold those are walked, one slot at
+// a time, so a long outage costs the same as a short one.
+func (s *Scheduler) missedSlots(slots []time.Duration, after, now time.Time) []time.Time {
+	if len(slots) == 0 {
+		return nil
+	}
+	slots = slices.Sorted(slices.Values(slots))
+	after = after.In(now.Location())
+
+	// a cycle holds every slot once, one more covers the current partial cycle
+	cycles := (MaxMissedRuns+len(slots)-1)/len(slots) + 1
+	if start := now.Add(-time.Duration(cycles) * s.cycle); after.Before(start) {
+		after = start
+	}
+
+	var missed []time.Time
+	for t := after; !s.atOffset(t, 0).After(now); t = s.nextCycle(t) {
+		for _, off := range slots {
+			fire := s.atOffset(t, off)
+			if !fire.After(after) || fire.After(now) {
+				continue
+			}
+			// wall-clock day cycles can repeat a time around DST changes
+			if len(missed) > 0 && !fire.After(missed[len(missed)-1]) {
+				continue
+			}
+			missed = appendMissed(missed, fire)
+		}
+	}
+	return missed
+}
+
+// nextCycle returns a time in the cycle following the one containing t.
+func (s *Scheduler) nextCycle(t time.Time) time.Time {
+	if s.cycle == hourCycle {
+		return t.Add(hourCycle)
+	}
+	// noon is never skipped nor repeated by DST changes
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 12, 0, 0, 0, t.Location())
+}
+
+// missed returns the cron fire times in (after, now], the last MaxMissedRuns of them.
+// Only a lookback from now is walked, doubled from a minute until it holds that many or
+// reaches after, so a job firing every second costs little after a long outage.
+func (cj *cronJob) missed(after, now time.Time) []time.Time {
+	for lookback := time.Minute; ; lookback *= 2 {
+		if lookback >= now.Sub(after) {
+			return cj.firesIn(after, now)
+		}
+		if missed := cj.firesIn(now.Add(-lookback), now); len(missed) == MaxMissedRuns {
+			return missed
+		}
+	}
+}
+
+// firesIn returns the cron fire times in (after, now], the last MaxMissedRuns of them.
+func (cj *cronJob) firesIn(after, now time.Time) []time.Time {
+	var missed []time.Time
+	for t := cj.nextAfter(after); !t.IsZero() && !t.After(now); t = cj.nextAfter(t) {
+		missed = appendMissed(missed, t)
+	}
+	return missed
+}
+
+// appendMissed appends t, dropping the oldest entry once MaxMissedRuns is reached.
+func appendMissed(missed []time.Time, t time.Time) []time.Time {
+	if len(missed) == MaxMissedRuns {
+		missed = missed[1:]
+	}
+	return append(missed, t)
+}
+
+// saveLastFire stores fireTime in the FireStore, if any.
+func (s *Scheduler) saveLastFire(id string, fireTime time.Time) {
+	if s.fireStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRecordTimeout)
+	defer cancel()
+	if err := s.fireStore.SetLastFire(ctx, id, fireTime); err != nil {
+		s.log.Warn().Str("job_id", id).Time("fire_time", fireTime).Err(err).Msg("Scheduler could not save last fire time")
+	}
+}
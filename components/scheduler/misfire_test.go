@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissedSlots(t *testing.T) {
+	s, err := New(15 * time.Minute)
+	require.NoError(t, err)
+
+	after := time.Date(2024, time.January, 10, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2024, time.January, 10, 12, 20, 0, 0, time.UTC)
+
+	missed := s.missedSlots([]time.Duration{0, 30 * time.Minute}, after, now)
+	require.Equal(t, []time.Time{
+		time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 11, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+	}, missed)
+
+	require.Empty(t, s.missedSlots([]time.Duration{45 * time.Minute}, now.Add(-time.Minute), now))
+}
+
+func TestMissedSlotsLongOutage(t *testing.T) {
+	tests := []struct {
+		name  string
+		tick  time.Duration
+		slots []time.Duration
+		last  time.Time
+		first time.Time
+	}{
+		{
+			name:  "hour cycle",
+			tick:  time.Second,
+			slots: []time.Duration{0, 30 * time.Minute},
+			last:  time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+			first: time.Date(2024, 1, 8, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "day cycle",
+			tick:  2 * time.Hour,
+			slots: []time.Duration{4 * time.Hour},
+			last:  time.Date(2024, 1, 10, 4, 0, 0, 0, time.UTC),
+			first: time.Date(2023, 10, 3, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.tick)
+			require.NoError(t, err)
+
+			// ten years down
+			now := time.Date(2024, time.January, 10, 12, 20, 0, 0, time.UTC)
+			missed := s.missedSlots(tt.slots, now.AddDate(-10, 0, 0), now)
+
+			require.Len(t, missed, MaxMissedRuns)
+			require.Equal(t, tt.first, missed[0])
+			require.Equal(t, tt.last, missed[len(missed)-1])
+			require.True(t, slices.IsSortedFunc(missed, time.Time.Compare))
+		})
+	}
+}
+
+func TestCronJobMissed(t *testing.T) {
+	schedule, err := ParseCron("0 3 * * *")
+	require.NoError(t, err)
+	cj := &cronJob{id: "nightly", schedule: schedule, location: time.UTC}
+
+	after := time.Date(2024, time.January, 10, 3, 0, 0, 0, time.UTC)
+	now := time.Date(2024, time.January, 13, 2, 0, 0, 0, time.UTC)
+
+	require.Equal(t, []time.Time{
+		time.Date(2024, 1, 11, 3, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 12, 3, 0, 0, 0, time.UTC),
+	}, cj.missed(after, now))
+
+	// only the most recent MaxMissedRuns are kept
+	missed := cj.missed(after.AddDate(-1, 0, 0), now)
+	require.Len(t, missed, MaxMissedRuns)
+	require.Equal(t, time.Date(2024, 1, 12, 3, 0, 0, 0, time.UTC), missed[len(missed)-1])
+}
+
+func TestCronJobMissedLongOutage(t *testing.T) {
+	schedule, err := ParseCron("* * * * * *")
+	require.NoError(t, err)
+	cj := &cronJob{id: "poll", schedule: schedule, location: time.UTC}
+
+	now := time.Date(2024, time.January, 10, 10, 0, 0, 500, time.UTC)
+	last := now.AddDate(0, 0, -30)
+
+	start := time.Now()
+	missed := cj.missed(last, now)
+	require.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, missed, MaxMissedRuns)
+	require.Equal(t, time.Date(2024, 1, 10, 9, 58, 21, 0, time.UTC), missed[0])
+	require.Equal(t, time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC), missed[len(missed)-1])
+	require.True(t, slices.IsSortedFunc(missed, time.Time.Compare))
+
+	// fire times sparser than the first lookback are still found
+	schedule, err = ParseCron("0 0 1 * *")
+	require.NoError(t, err)
+	cj = &cronJob{id: "monthly", schedule: schedule, location: time.UTC}
+
+	require.Equal(t, []time.Time{
+		time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, cj.missed(time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC), now))
+}
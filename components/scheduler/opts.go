@@ -9,6 +9,8 @@ import (
 const (
 	DefaultShutdownTimeout = 60 * time.Second
 	DefaultJobTimeout      = time.Hour
+	DefaultLeaseTTL        = 30 * time.Second
+	DefaultRecordTimeout   = 10 * time.Second
 )
 
 // Option configures the Scheduler.
@@ -25,6 +27,17 @@ func WithLogger(l logger.Logger) Option {
 	}
 }
 
+// WithClock sets the Clock used for alignment, ticks and timestamps. Nil => real time.
+func WithClock(c Clock) Option {
+	return func(s *Scheduler) {
+		if c == nil {
+			s.clock = realClock{}
+			return
+		}
+		s.clock = c
+	}
+}
+
 // WithShutdownTimeout sets how long Start() waits for jobs to drain after ctx cancel.
 func WithShutdownTimeout(d time.Duration) Option {
 	return func(s *Scheduler) {
@@ -42,3 +55,118 @@ func WithJobTimeout(d time.Duration) Option {
 		}
 	}
 }
+
+// WithLocker sets the Locker used to make sure each job fire time runs on a single replica.
+// Nil => jobs are only de-duplicated within this process.
+func WithLocker(l Locker) Option {
+	return func(s *Scheduler) {
+		s.locker = l
+	}
+}
+
+// WithLeaseTTL sets how long a Locker lease is held before it must be renewed.
+// Leases are renewed every third of the TTL while the job runs.
+func WithLeaseTTL(d time.Duration) Option {
+	return func(s *Scheduler) {
+		if d > 0 {
+			s.leaseTTL = d
+		}
+	}
+}
+
+// WithRecorder sets the Recorder that stores the outcome of every job run.
+// Nil => runs are only logged.
+func WithRecorder(r Recorder) Option {
+	return func(s *Scheduler) {
+		s.recorder = r
+	}
+}
+
+// WithFireStore sets the FireStore used to persist each job's last fire time.
+// It is required for any MisfirePolicy other than MisfireSkip to take effect.
+func WithFireStore(fs FireStore) Option {
+	return func(s *Scheduler) {
+		s.fireStore = fs
+	}
+}
+
+// WithMaxConcurrency limits how many jobs run at the same time across all ids.
+// Up to queue further runs wait for a free slot, in order; runs beyond that are skipped.
+// n <= 0 => unlimited.
+func WithMaxConcurrency(n, queue int) Option {
+	return func(s *Scheduler) {
+		if n <= 0 {
+			s.sem = nil
+			s.maxWaiting = 0
+			return
+		}
+		s.sem = make(chan struct{}, n)
+		s.maxWaiting = max(queue, 0)
+	}
+}
+
+// WithJitter sets the default Jitter of scheduled jobs, applied from the first aligned tick on.
+// Jobs can override it with JobJitter. One-shot and triggered runs are never delayed.
+func WithJitter(j Jitter) Option {
+	return func(s *Scheduler) {
+		s.jitter = j
+	}
+}
+
+// JobOption configures a single job registered on the Scheduler.
+type JobOption func(*jobOptions)
+
+type jobOptions struct {
+	timezone string
+	retry    RetryPolicy
+	misfire  MisfirePolicy
+	overlap  OverlapPolicy
+	jitter   *Jitter // nil => the scheduler's WithJitter
+}
+
+// JobTimezone sets the IANA timezone (e.g. "America/Mexico_City") a cron job is evaluated in.
+// It takes precedence over a CRON_TZ prefix in the expression. Defaults to the local timezone.
+func JobTimezone(tz string) JobOption {
+	return func(o *jobOptions) {
+		o.timezone = tz
+	}
+}
+
+// JobRetry sets the policy used to retry an ErrorJob that returns an error.
+// Jobs are not retried by default.
+func JobRetry(p RetryPolicy) JobOption {
+	return func(o *jobOptions) {
+		o.retry = p
+	}
+}
+
+// JobMisfire sets what to do on Start with fire times missed while the scheduler was down.
+// Defaults to MisfireSkip.
+func JobMisfire(p MisfirePolicy) JobOption {
+	return func(o *jobOptions) {
+		o.misfire = p
+	}
+}
+
+// JobOverlap sets what to do when the job fires while its previous run is still going.
+// Defaults to OverlapSkip.
+func JobOverlap(p OverlapPolicy) JobOption {
+	return func(o *jobOptions) {
+		o.overlap = p
+	}
+}
+
+// JobJitter sets the Jitter of the job, overriding WithJitter. Jitter{} disables it for the job.
+func JobJitter(j Jitter) JobOption {
+	return func(o *jobOptions) {
+		o.jitter = &j
+	}
+}
+
+func newJobOptions(opts []JobOption) *jobOptions {
+	o := &jobOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
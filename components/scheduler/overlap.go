@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// OverlapPolicy decides what happens when a job fires while its previous run is still going.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip drops the new run. This is the default.
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue keeps a single pending run that starts as soon as the current one finishes.
+	// Further runs fired while one is already pending are skipped.
+	OverlapQueue OverlapPolicy = "queue"
+	// OverlapReplace cancels the current run and starts the new one once it returns.
+	// Jobs must honor ctx cancellation for the replacement to start promptly.
+	OverlapReplace OverlapPolicy = "replace"
+)
+
+// errReplaced is the cancel cause of a run superseded by OverlapReplace.
+var errReplaced = errors.New("replaced by a newer run")
+
+// pendingRun is a run waiting for the current run of the same id to finish.
+type pendingRun struct {
+	ctx      context.Context
+	job      ErrorJob
+	fireTime time.Time
+	opts     *jobOptions
+	jitter   time.Duration // delay applied after fireTime, see Jitter
+}
+
+// activeRun tracks the in-flight run of a job id and the runs queued behind it.
+type activeRun struct {
+	cancel  context.CancelCauseFunc // cancels the current run
+	pending []pendingRun
+}
+
+// overlap applies the run's OverlapPolicy to an id that is already running.
+// Must be called with runMu held. Returns false if the run was skipped.
+func (ar *activeRun) overlap(run pendingRun) bool {
+	switch run.opts.overlap {
+	case OverlapQueue:
+		if len(ar.pending) > 0 {
+			return false
+		}
+		ar.pending = append(ar.pending, run)
+		return true
+	case OverlapReplace:
+		ar.pending = []pendingRun{run}
+		if ar.cancel != nil {
+			ar.cancel(errReplaced)
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// spawnRuns starts the given runs of id one after the other in a new goroutine.
+// If id is already running, the last run is handled by its OverlapPolicy instead.
+// Returns true if the runs were started or queued, false if skipped.
+func (s *Scheduler) spawnRuns(id string, runs []pendingRun) bool {
+	if id == "" || len(runs) == 0 || runs[0].ctx.Err() != nil {
+		return false
+	}
+
+	s.runMu.Lock()
+	if ar, busy := s.running[id]; busy {
+		last := runs[len(runs)-1]
+		queued := ar.overlap(last)
+		s.runMu.Unlock()
+
+		if !queued {
+			s.recordRun(Run{JobID: id, FireTime: last.fireTime, Status: RunStatusSkipped})
+			return false
+		}
+		s.log.Debug().
+			Str("job_id", id).
+			Time("fire_time", last.fireTime).
+			Str("policy", string(last.opts.overlap)).
+			Msg("Job already running, run queued")
+		return true
+	}
+	ar := &activeRun{pending: runs}
+	s.running[id] = ar
+	s.runMu.Unlock()
+
+	atomic.AddInt64(&s.activeJobs, 1)
+	s.wg.Add(1)
+
+	go func() {
+		defer s.release()
+		for {
+			run, cancel, ok := s.nextRun(id, ar)
+			if !ok {
+				return
+			}
+			if s.acquireSlot(run.ctx, id, run.fireTime) {
+				s.execute(run.ctx, id, run.job, run.fireTime, run.opts, run.jitter)
+				s.releaseSlot()
+			}
+			cancel(nil)
+		}
+	}()
+
+	return true
+}
+
+// nextRun pops the next pending run of id, bound to a context that OverlapReplace can cancel.
+// When nothing is pending id is no longer marked as running and ok is false.
+func (s *Scheduler) nextRun(id string, ar *activeRun) (pendingRun, context.CancelCauseFunc, bool) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	for len(ar.pending) > 0 {
+		run := ar.pending[0]
+		ar.pending = ar.pending[1:]
+		// runs bound to a context that's already done (e.g. shutdown) are dropped
+		if run.ctx.Err() != nil {
+			continue
+		}
+
+		run.ctx, ar.cancel = context.WithCancelCause(run.ctx)
+		return run, ar.cancel, true
+	}
+
+	delete(s.running, id)
+	return pendingRun{}, nil, false
+}
+
+// release signals Idled when no jobs are left.
+func (s *Scheduler) release() {
+	defer s.wg.Done()
+
+	if atomic.AddInt64(&s.activeJobs, -1) == 0 {
+		if ch := s.idledCh; ch != nil {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// acquireSlot blocks until the run may start under the scheduler-wide concurrency limit.
+// Returns false if the wait queue is full or ctx is done first, in which case the run is skipped.
+func (s *Scheduler) acquireSlot(ctx context.Context, id string, fireTime time.Time) bool {
+	if s.sem == nil {
+		return true
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	default:
+	}
+
+	if atomic.AddInt64(&s.waiting, 1) > int64(s.maxWaiting) {
+		atomic.AddInt64(&s.waiting, -1)
+		s.log.Warn().Str("job_id", id).Time("fire_time", fireTime).Msg("Scheduler concurrency queue full, skipping job")
+		s.recordRun(Run{JobID: id, FireTime: fireTime, Status: RunStatusSkipped, Error: "concurrency queue full"})
+		return false
+	}
+	defer atomic.AddInt64(&s.waiting, -1)
+
+	s.log.Debug().Str("job_id", id).Time("fire_time", fireTime).Msg("Scheduler concurrency limit reached, job waiting")
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// releaseSlot frees the concurrency slot taken by acquireSlot.
+func (s *Scheduler) releaseSlot() {
+	if s.sem != nil {
+		<-s.sem
+	}
+}
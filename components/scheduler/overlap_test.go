@@ -0,0 +1,96 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/scheduler"
+)
+
+func statuses(runs []scheduler.Run) []scheduler.RunStatus {
+	out := make([]scheduler.RunStatus, 0, len(runs))
+	for _, r := range runs {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func TestOverlapQueue(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC))
+
+	finish := make(chan struct{})
+	calls := make(chan struct{}, 3)
+	job := func(ctx context.Context) {
+		calls <- struct{}{}
+		<-finish
+	}
+	queue := scheduler.JobOverlap(scheduler.OverlapQueue)
+
+	ctx := context.Background()
+	require.True(t, h.s.RunOnce(ctx, "export", job, queue))
+	<-calls
+	require.True(t, h.s.RunOnce(ctx, "export", job, queue))
+	require.False(t, h.s.RunOnce(ctx, "export", job, queue))
+
+	close(finish)
+	runs, ok := h.recorder.WaitRuns(3, waitTimeout)
+	require.True(t, ok)
+	require.Len(t, calls, 1)
+	require.ElementsMatch(t, []scheduler.RunStatus{
+		scheduler.RunStatusSkipped,
+		scheduler.RunStatusOK,
+		scheduler.RunStatusOK,
+	}, statuses(runs))
+}
+
+func TestOverlapReplace(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC))
+
+	started := make(chan struct{})
+	replace := scheduler.JobOverlap(scheduler.OverlapReplace)
+
+	ctx := context.Background()
+	require.True(t, h.s.RunOnceErr(ctx, "sync", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, replace))
+	<-started
+	require.True(t, h.s.RunOnce(ctx, "sync", noop, replace))
+
+	runs, ok := h.recorder.WaitRuns(2, waitTimeout)
+	require.True(t, ok)
+	require.ElementsMatch(t, []scheduler.RunStatus{scheduler.RunStatusCanceled, scheduler.RunStatusOK}, statuses(runs))
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC),
+		scheduler.WithMaxConcurrency(1, 1),
+	)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	ctx := context.Background()
+	require.True(t, h.s.RunOnce(ctx, "heavy", func(ctx context.Context) {
+		close(started)
+		<-finish
+	}))
+	<-started
+
+	require.True(t, h.s.RunOnce(ctx, "waits", noop))
+	require.Eventually(t, func() bool { return h.s.Waiting() == 1 }, waitTimeout, time.Millisecond)
+
+	require.True(t, h.s.RunOnce(ctx, "dropped", noop))
+	runs, ok := h.recorder.WaitRuns(1, waitTimeout)
+	require.True(t, ok)
+	require.Equal(t, "dropped", runs[0].JobID)
+	require.Equal(t, scheduler.RunStatusSkipped, runs[0].Status)
+
+	close(finish)
+	runs, ok = h.recorder.WaitRuns(3, waitTimeout)
+	require.True(t, ok)
+	require.ElementsMatch(t, []string{"heavy", "waits"}, []string{runs[1].JobID, runs[2].JobID})
+	require.Equal(t, []scheduler.RunStatus{scheduler.RunStatusOK, scheduler.RunStatusOK}, statuses(runs[1:]))
+}
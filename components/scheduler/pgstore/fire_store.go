@@ -0,0 +1,75 @@
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/compose/drivers/databases/relational"
+	"github.com/vanclief/ez"
+)
+
+// JobFire is the row holding the last fire time of a job ID.
+type JobFire struct {
+	bun.BaseModel `bun:"table:scheduler_job_fires,alias:job_fire"`
+
+	JobID        string    `bun:"job_id,pk"`
+	LastFireTime time.Time `bun:"last_fire_time,notnull"`
+}
+
+// FireStore implements scheduler.FireStore in Postgres.
+type FireStore struct {
+	db *relational.DB
+}
+
+// NewFireStore creates the fire time table if it doesn't exist.
+func NewFireStore(db *relational.DB) (*FireStore, error) {
+	if db == nil {
+		return nil, ez.New(ez.EINVALID, "db cannot be nil", nil)
+	}
+
+	err := db.CreateTables([]interface{}{(*JobFire)(nil)})
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	return &FireStore{db: db}, nil
+}
+
+// LastFire implements scheduler.FireStore.
+func (fs *FireStore) LastFire(ctx context.Context, jobID string) (time.Time, bool, error) {
+	row := new(JobFire)
+
+	err := fs.db.NewSelect().
+		Model(row).
+		Where("job_id = ?", jobID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	} else if err != nil {
+		return time.Time{}, false, ez.Wrap(err)
+	}
+
+	return row.LastFireTime, true, nil
+}
+
+// SetLastFire implements scheduler.FireStore.
+func (fs *FireStore) SetLastFire(ctx context.Context, jobID string, fireTime time.Time) error {
+	row := &JobFire{JobID: jobID, LastFireTime: fireTime}
+
+	_, err := fs.db.NewInsert().
+		Model(row).
+		On("CONFLICT (job_id) DO UPDATE").
+		Set("last_fire_time = GREATEST(job_fire.last_fire_time, EXCLUDED.last_fire_time)").
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	return nil
+}
+
+var _ scheduler.FireStore = (*FireStore)(nil)
@@ -0,0 +1,40 @@
+package pgstore
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *TestSuite) TestFireStore() {
+	ctx := context.Background()
+	fs, err := NewFireStore(suite.db)
+	suite.Require().NoError(err)
+
+	_, ok, err := fs.LastFire(ctx, "report")
+	suite.Require().NoError(err)
+	suite.False(ok)
+
+	fireTime := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	suite.Require().NoError(fs.SetLastFire(ctx, "report", fireTime))
+
+	last, ok, err := fs.LastFire(ctx, "report")
+	suite.Require().NoError(err)
+	suite.True(ok)
+	suite.True(fireTime.Equal(last))
+
+	// a replica saving an older fire time late doesn't move it back
+	suite.Require().NoError(fs.SetLastFire(ctx, "report", fireTime.Add(-time.Hour)))
+	last, _, err = fs.LastFire(ctx, "report")
+	suite.Require().NoError(err)
+	suite.True(fireTime.Equal(last))
+
+	suite.Require().NoError(fs.SetLastFire(ctx, "report", fireTime.Add(time.Hour)))
+	last, _, err = fs.LastFire(ctx, "report")
+	suite.Require().NoError(err)
+	suite.True(fireTime.Add(time.Hour).Equal(last))
+
+	// other jobs are independent
+	_, ok, err = fs.LastFire(ctx, "cleanup")
+	suite.Require().NoError(err)
+	suite.False(ok)
+}
@@ -0,0 +1,137 @@
+package pgstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/compose/drivers/databases/relational"
+	"github.com/vanclief/ez"
+)
+
+// JobLease is the row holding the lease of a job ID. It is kept after the lease
+// is released so the same fire time can't be claimed twice.
+type JobLease struct {
+	bun.BaseModel `bun:"table:scheduler_job_leases,alias:job_lease"`
+
+	JobID     string    `bun:"job_id,pk"`
+	FireTime  time.Time `bun:"fire_time,notnull"`
+	Owner     string    `bun:"owner,notnull"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"`
+}
+
+// Locker implements scheduler.Locker with a lease table in Postgres.
+// A job ID can be claimed for a fire time only if that fire time is newer than the
+// last claimed one and the previous lease was released or expired.
+// Expirations are computed with the database clock, so replica clock skew doesn't matter.
+type Locker struct {
+	db    *relational.DB
+	owner string
+}
+
+// NewLocker creates the lease table if it doesn't exist and returns a Locker
+// identified by the host name and a random suffix.
+func NewLocker(db *relational.DB) (*Locker, error) {
+	if db == nil {
+		return nil, ez.New(ez.EINVALID, "db cannot be nil", nil)
+	}
+
+	err := db.CreateTables([]interface{}{(*JobLease)(nil)})
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%s", host, uuid.NewString()[:8])
+
+	return &Locker{db: db, owner: owner}, nil
+}
+
+// Owner returns the identifier this Locker writes on the leases it holds.
+func (l *Locker) Owner() string {
+	return l.owner
+}
+
+// Acquire implements scheduler.Locker.
+func (l *Locker) Acquire(ctx context.Context, jobID string, fireTime time.Time, ttl time.Duration) (scheduler.Lease, bool, error) {
+	row := &JobLease{JobID: jobID, FireTime: fireTime, Owner: l.owner}
+
+	res, err := l.db.NewInsert().
+		Model(row).
+		Value("expires_at", "now() + ? * interval '1 millisecond'", ttl.Milliseconds()).
+		On("CONFLICT (job_id) DO UPDATE").
+		Set("fire_time = EXCLUDED.fire_time").
+		Set("owner = EXCLUDED.owner").
+		Set("expires_at = EXCLUDED.expires_at").
+		Where("job_lease.fire_time < EXCLUDED.fire_time").
+		Where("job_lease.expires_at <= now()").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return nil, false, ez.Wrap(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, ez.Wrap(err)
+	} else if affected == 0 {
+		return nil, false, nil
+	}
+
+	return &lease{locker: l, jobID: jobID, fireTime: fireTime}, true, nil
+}
+
+// lease is a JobLease held by this Locker.
+type lease struct {
+	locker   *Locker
+	jobID    string
+	fireTime time.Time
+}
+
+// Renew implements scheduler.Lease.
+func (le *lease) Renew(ctx context.Context, ttl time.Duration) error {
+	res, err := le.locker.db.NewUpdate().
+		Model((*JobLease)(nil)).
+		Set("expires_at = now() + ? * interval '1 millisecond'", ttl.Milliseconds()).
+		Where("job_id = ?", le.jobID).
+		Where("fire_time = ?", le.fireTime).
+		Where("owner = ?", le.locker.owner).
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return ez.Wrap(err)
+	} else if affected == 0 {
+		return ez.New(ez.ECONFLICT, "job lease is no longer held", nil)
+	}
+
+	return nil
+}
+
+// Release implements scheduler.Lease.
+func (le *lease) Release(ctx context.Context) error {
+	_, err := le.locker.db.NewUpdate().
+		Model((*JobLease)(nil)).
+		Set("expires_at = now()").
+		Where("job_id = ?", le.jobID).
+		Where("fire_time = ?", le.fireTime).
+		Where("owner = ?", le.locker.owner).
+		Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	return nil
+}
+
+var (
+	_ scheduler.Locker = (*Locker)(nil)
+	_ scheduler.Lease  = (*lease)(nil)
+)
@@ -0,0 +1,135 @@
+package pgstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+func (suite *TestSuite) newLockers(n int) []*Locker {
+	lockers := make([]*Locker, n)
+	for i := range lockers {
+		l, err := NewLocker(suite.db)
+		suite.Require().NoError(err)
+		lockers[i] = l
+	}
+	return lockers
+}
+
+func (suite *TestSuite) TestLockerConcurrentAcquire() {
+	ctx := context.Background()
+	fireTime := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	var acquired, failed atomic.Int32
+	var wg sync.WaitGroup
+	for _, l := range suite.newLockers(10) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := l.Acquire(ctx, "report", fireTime, time.Minute)
+			switch {
+			case err != nil:
+				failed.Add(1)
+			case ok:
+				acquired.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.Zero(failed.Load())
+	suite.Equal(int32(1), acquired.Load())
+}
+
+func (suite *TestSuite) TestLockerFireTimeOnce() {
+	ctx := context.Background()
+	lockers := suite.newLockers(2)
+	fireTime := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	lease, ok, err := lockers[0].Acquire(ctx, "report", fireTime, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().True(ok)
+
+	// held: not even a newer fire time can be claimed
+	_, ok, err = lockers[1].Acquire(ctx, "report", fireTime.Add(time.Minute), time.Minute)
+	suite.Require().NoError(err)
+	suite.False(ok)
+
+	// released: the same fire time still can't run twice, a newer one can
+	suite.Require().NoError(lease.Release(ctx))
+
+	_, ok, err = lockers[1].Acquire(ctx, "report", fireTime, time.Minute)
+	suite.Require().NoError(err)
+	suite.False(ok)
+
+	_, ok, err = lockers[1].Acquire(ctx, "report", fireTime.Add(time.Minute), time.Minute)
+	suite.Require().NoError(err)
+	suite.True(ok)
+
+	// other jobs are independent
+	_, ok, err = lockers[0].Acquire(ctx, "cleanup", fireTime, time.Minute)
+	suite.Require().NoError(err)
+	suite.True(ok)
+}
+
+func (suite *TestSuite) TestLockerExpiryTakeover() {
+	ctx := context.Background()
+	lockers := suite.newLockers(2)
+	fireTime := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	ttl := 200 * time.Millisecond
+
+	lease, ok, err := lockers[0].Acquire(ctx, "report", fireTime, ttl)
+	suite.Require().NoError(err)
+	suite.Require().True(ok)
+
+	_, ok, err = lockers[1].Acquire(ctx, "report", fireTime.Add(time.Minute), ttl)
+	suite.Require().NoError(err)
+	suite.False(ok)
+
+	// the replica holding the lease died without releasing it
+	time.Sleep(ttl + 50*time.Millisecond)
+
+	_, ok, err = lockers[1].Acquire(ctx, "report", fireTime.Add(time.Minute), ttl)
+	suite.Require().NoError(err)
+	suite.True(ok)
+
+	// the lost lease can't be renewed nor released over the new one
+	err = lease.Renew(ctx, ttl)
+	suite.Equal(ez.ECONFLICT, ez.ErrorCode(err))
+	suite.NoError(lease.Release(ctx))
+
+	_, ok, err = lockers[0].Acquire(ctx, "report", fireTime.Add(2*time.Minute), ttl)
+	suite.Require().NoError(err)
+	suite.False(ok)
+}
+
+func (suite *TestSuite) TestLockerRenewal() {
+	ctx := context.Background()
+	lockers := suite.newLockers(2)
+	fireTime := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	ttl := 300 * time.Millisecond
+
+	lease, ok, err := lockers[0].Acquire(ctx, "report", fireTime, ttl)
+	suite.Require().NoError(err)
+	suite.Require().True(ok)
+
+	// renewed every third of the TTL as the scheduler does, the lease outlives its TTL
+	for range 9 {
+		time.Sleep(ttl / 3)
+		suite.Require().NoError(lease.Renew(ctx, ttl))
+
+		_, ok, err = lockers[1].Acquire(ctx, "report", fireTime.Add(time.Minute), ttl)
+		suite.Require().NoError(err)
+		suite.Require().False(ok)
+	}
+
+	// once renewals stop it expires a TTL after the last one
+	time.Sleep(ttl + 50*time.Millisecond)
+
+	_, ok, err = lockers[1].Acquire(ctx, "report", fireTime.Add(time.Minute), ttl)
+	suite.Require().NoError(err)
+	suite.True(ok)
+}
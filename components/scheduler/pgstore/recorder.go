@@ -0,0 +1,192 @@
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/compose/drivers/databases/relational"
+	"github.com/vanclief/ez"
+)
+
+// JobRun is the stored history row of a scheduler.Run.
+type JobRun struct {
+	bun.BaseModel `bun:"table:scheduler_job_runs,alias:job_run"`
+
+	ID         int64               `bun:"id,pk,autoincrement"`
+	JobID      string              `bun:"job_id,notnull"`
+	FireTime   time.Time           `bun:"fire_time,notnull"`
+	StartedAt  time.Time           `bun:"started_at,nullzero"`
+	EndedAt    time.Time           `bun:"ended_at,nullzero"`
+	DurationMS int64               `bun:"duration_ms,notnull"`
+	Attempts   int                 `bun:"attempts,notnull"`
+	Status     scheduler.RunStatus `bun:"status,notnull"`
+	Error      string              `bun:"error,nullzero"`
+	PanicStack string              `bun:"panic_stack,nullzero"`
+}
+
+// Run converts the row back to a scheduler.Run.
+func (r *JobRun) Run() scheduler.Run {
+	return scheduler.Run{
+		JobID:      r.JobID,
+		FireTime:   r.FireTime,
+		StartedAt:  r.StartedAt,
+		EndedAt:    r.EndedAt,
+		Duration:   time.Duration(r.DurationMS) * time.Millisecond,
+		Attempts:   r.Attempts,
+		Status:     r.Status,
+		Error:      r.Error,
+		PanicStack: r.PanicStack,
+	}
+}
+
+// failedStatuses are the outcomes returned by RecentFailures.
+var failedStatuses = []scheduler.RunStatus{
+	scheduler.RunStatusError,
+	scheduler.RunStatusPanic,
+	scheduler.RunStatusTimeout,
+}
+
+// Recorder implements scheduler.Recorder by inserting every run into Postgres.
+type Recorder struct {
+	db *relational.DB
+}
+
+// NewRecorder creates the run history table and its index if they don't exist.
+func NewRecorder(db *relational.DB) (*Recorder, error) {
+	if db == nil {
+		return nil, ez.New(ez.EINVALID, "db cannot be nil", nil)
+	}
+
+	err := db.CreateTables([]interface{}{(*JobRun)(nil)})
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	_, err = db.NewCreateIndex().
+		Model((*JobRun)(nil)).
+		Index("scheduler_job_runs_job_id_id_idx").
+		Column("job_id", "id").
+		IfNotExists().
+		Exec(context.Background())
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	return &Recorder{db: db}, nil
+}
+
+// Record implements scheduler.Recorder.
+func (r *Recorder) Record(ctx context.Context, run scheduler.Run) error {
+	row := &JobRun{
+		JobID:      run.JobID,
+		FireTime:   run.FireTime,
+		StartedAt:  run.StartedAt,
+		EndedAt:    run.EndedAt,
+		DurationMS: run.Duration.Milliseconds(),
+		Attempts:   run.Attempts,
+		Status:     run.Status,
+		Error:      run.Error,
+		PanicStack: run.PanicStack,
+	}
+
+	_, err := r.db.NewInsert().Model(row).Exec(ctx)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	return nil
+}
+
+// LastRun returns the most recent run of jobID, skipped runs included.
+func (r *Recorder) LastRun(ctx context.Context, jobID string) (*JobRun, error) {
+	run := new(JobRun)
+
+	err := r.db.NewSelect().
+		Model(run).
+		Where("job_id = ?", jobID).
+		Order("id DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, r.notFound(err, jobID)
+	}
+
+	return run, nil
+}
+
+// LastSuccess returns the most recent run of jobID that finished without error.
+func (r *Recorder) LastSuccess(ctx context.Context, jobID string) (*JobRun, error) {
+	run := new(JobRun)
+
+	err := r.db.NewSelect().
+		Model(run).
+		Where("job_id = ?", jobID).
+		Where("status = ?", scheduler.RunStatusOK).
+		Order("id DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, r.notFound(err, jobID)
+	}
+
+	return run, nil
+}
+
+// RecentFailures returns up to limit of the most recent errored, panicked or
+// timed out runs, newest first. An empty jobID returns failures of every job.
+func (r *Recorder) RecentFailures(ctx context.Context, jobID string, limit int) ([]JobRun, error) {
+	if limit <= 0 {
+		return nil, ez.New(ez.EINVALID, "limit must be positive", nil)
+	}
+
+	runs := []JobRun{}
+	query := r.db.NewSelect().
+		Model(&runs).
+		Where("status IN (?)", bun.In(failedStatuses)).
+		Order("id DESC").
+		Limit(limit)
+
+	if jobID != "" {
+		query = query.Where("job_id = ?", jobID)
+	}
+
+	err := query.Scan(ctx)
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	return runs, nil
+}
+
+// Prune deletes runs whose fire time is before the given time.
+func (r *Recorder) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.NewDelete().
+		Model((*JobRun)(nil)).
+		Where("fire_time < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, ez.Wrap(err)
+	}
+
+	return deleted, nil
+}
+
+func (r *Recorder) notFound(err error, jobID string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		errMsg := fmt.Sprintf("no runs found for job %s", jobID)
+		return ez.New(ez.ENOTFOUND, errMsg, err)
+	}
+	return ez.Wrap(err)
+}
+
+var _ scheduler.Recorder = (*Recorder)(nil)
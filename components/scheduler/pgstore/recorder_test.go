@@ -0,0 +1,77 @@
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/ez"
+)
+
+func (suite *TestSuite) TestRecorder() {
+	ctx := context.Background()
+	r, err := NewRecorder(suite.db)
+	suite.Require().NoError(err)
+
+	start := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	runs := []scheduler.Run{
+		{JobID: "report", Status: scheduler.RunStatusOK},
+		{JobID: "report", Status: scheduler.RunStatusError, Error: "timeout dialing smtp"},
+		{JobID: "cleanup", Status: scheduler.RunStatusPanic, PanicStack: "goroutine 1"},
+		{JobID: "report", Status: scheduler.RunStatusTimeout},
+		{JobID: "report", Status: scheduler.RunStatusSkipped},
+	}
+	for i, run := range runs {
+		run.FireTime = start.Add(time.Duration(i) * time.Hour)
+		run.StartedAt = run.FireTime
+		run.EndedAt = run.FireTime.Add(1500 * time.Millisecond)
+		run.Duration = 1500 * time.Millisecond
+		run.Attempts = 1
+		suite.Require().NoError(r.Record(ctx, run))
+	}
+
+	// skipped runs count as the last run, not as a success
+	last, err := r.LastRun(ctx, "report")
+	suite.Require().NoError(err)
+	suite.Equal(scheduler.RunStatusSkipped, last.Status)
+	suite.Equal(start.Add(4*time.Hour), last.FireTime.UTC())
+
+	success, err := r.LastSuccess(ctx, "report")
+	suite.Require().NoError(err)
+	suite.Equal(start, success.FireTime.UTC())
+	suite.Equal(1500*time.Millisecond, success.Run().Duration)
+
+	_, err = r.LastSuccess(ctx, "cleanup")
+	suite.Equal(ez.ENOTFOUND, ez.ErrorCode(err))
+	_, err = r.LastRun(ctx, "unknown")
+	suite.Equal(ez.ENOTFOUND, ez.ErrorCode(err))
+
+	// newest first, errors, panics and timeouts only
+	failures, err := r.RecentFailures(ctx, "report", 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(failures, 2)
+	suite.Equal(scheduler.RunStatusTimeout, failures[0].Status)
+	suite.Equal(scheduler.RunStatusError, failures[1].Status)
+	suite.Equal("timeout dialing smtp", failures[1].Error)
+
+	failures, err = r.RecentFailures(ctx, "", 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(failures, 2)
+	suite.Equal("report", failures[0].JobID)
+	suite.Equal("cleanup", failures[1].JobID)
+	suite.Equal("goroutine 1", failures[1].PanicStack)
+
+	_, err = r.RecentFailures(ctx, "", 0)
+	suite.Equal(ez.EINVALID, ez.ErrorCode(err))
+
+	// prune keeps the runs fired at or after the given time
+	deleted, err := r.Prune(ctx, start.Add(3*time.Hour))
+	suite.Require().NoError(err)
+	suite.Equal(int64(3), deleted)
+
+	_, err = r.LastSuccess(ctx, "report")
+	suite.Equal(ez.ENOTFOUND, ez.ErrorCode(err))
+	last, err = r.LastRun(ctx, "report")
+	suite.Require().NoError(err)
+	suite.Equal(scheduler.RunStatusSkipped, last.Status)
+}
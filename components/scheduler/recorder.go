@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// RunStatus is the outcome of a job run.
+type RunStatus string
+
+const (
+	RunStatusOK       RunStatus = "ok"
+	RunStatusError    RunStatus = "error"
+	RunStatusPanic    RunStatus = "panic"
+	RunStatusTimeout  RunStatus = "timeout"
+	RunStatusSkipped  RunStatus = "skipped"  // the previous run was still running or the concurrency queue was full
+	RunStatusCanceled RunStatus = "canceled" // replaced by a newer run, see OverlapReplace
+)
+
+// Run describes a single execution (or skip) of a job.
+type Run struct {
+	JobID      string
+	FireTime   time.Time
+	StartedAt  time.Time // zero for skipped runs
+	EndedAt    time.Time // zero for skipped runs
+	Duration   time.Duration
+	Attempts   int // 0 for skipped runs
+	Status     RunStatus
+	Error      string
+	PanicStack string
+}
+
+// Recorder persists the history of job runs.
+// Record is called once per run after it ends, and once per skipped fire time.
+// Implementations MUST be safe for concurrent use.
+type Recorder interface {
+	Record(ctx context.Context, run Run) error
+}
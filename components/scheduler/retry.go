@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/vanclief/ez"
+)
+
+const (
+	DefaultRetryInitialBackoff = time.Second
+	DefaultRetryMaxBackoff     = time.Minute
+	DefaultRetryMultiplier     = 2.0
+)
+
+// RetryPolicy controls how an ErrorJob is retried within a single run.
+// Retries stop early when the job context is done, either because the job
+// timeout (see WithJobTimeout) would be exceeded or because the scheduler is shutting down.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one; <= 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Defaults to DefaultRetryInitialBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential growth. Defaults to DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every retry. Defaults to DefaultRetryMultiplier.
+	Multiplier float64
+	// Jitter randomizes each backoff by up to this fraction (0..1) of its value.
+	Jitter float64
+	// RetryOn restricts retries to errors with one of these ez codes, e.g. ez.EINTERNAL.
+	// Empty retries every error.
+	RetryOn []string
+}
+
+// shouldRetry reports whether err is eligible for another attempt.
+func (p RetryPolicy) shouldRetry(err error) bool {
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+	return slices.Contains(p.RetryOn, ez.ErrorCode(err))
+}
+
+// backoff returns the wait before the given retry (1 for the first retry).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultRetryInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	d := float64(initial)
+	for i := 1; i < retry && d < float64(maxBackoff); i++ {
+		d *= multiplier
+	}
+	d = min(d, float64(maxBackoff))
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		// keep (1 - jitter) of the backoff and randomize the rest
+		d = d*(1-jitter) + rand.Float64()*d*jitter
+	}
+
+	return time.Duration(d)
+}
+
+// runWithRetry runs job until it succeeds, the policy gives up or ctx is done.
+// It returns the number of attempts made and the last error.
+func (s *Scheduler) runWithRetry(ctx context.Context, id string, job ErrorJob, p RetryPolicy) (int, error) {
+	attempt := 0
+	for {
+		attempt++
+		err := job(ctx)
+		if err == nil {
+			return attempt, nil
+		}
+		if attempt >= p.MaxAttempts || !p.shouldRetry(err) || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		wait := p.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return attempt, err
+		}
+
+		s.log.Warn().
+			Str("job_id", id).
+			Int("attempt", attempt).
+			Dur("backoff", wait).
+			Err(err).
+			Msg("Scheduler job attempt failed, retrying")
+
+		timer := s.clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C():
+		}
+	}
+}
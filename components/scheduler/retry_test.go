@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/ez"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	require.Equal(t, 100*time.Millisecond, p.backoff(1))
+	require.Equal(t, 200*time.Millisecond, p.backoff(2))
+	require.Equal(t, 800*time.Millisecond, p.backoff(4))
+	require.Equal(t, time.Second, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(2)
+		require.GreaterOrEqual(t, d, 100*time.Millisecond)
+		require.LessOrEqual(t, d, 200*time.Millisecond)
+	}
+}
+
+func TestRunWithRetry(t *testing.T) {
+	s, err := New(time.Minute)
+	require.NoError(t, err)
+
+	p := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		calls := 0
+		attempts, err := s.runWithRetry(context.Background(), "job", func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return ez.New(ez.EINTERNAL, "boom", nil)
+			}
+			return nil
+		}, p)
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		attempts, err := s.runWithRetry(context.Background(), "job", func(ctx context.Context) error {
+			return ez.New(ez.EINTERNAL, "boom", nil)
+		}, p)
+		require.Error(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("only retries matching codes", func(t *testing.T) {
+		p := p
+		p.RetryOn = []string{ez.EINTERNAL}
+		attempts, err := s.runWithRetry(context.Background(), "job", func(ctx context.Context) error {
+			return ez.New(ez.EINVALID, "bad input", nil)
+		}, p)
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("stops when backoff exceeds the deadline", func(t *testing.T) {
+		p := p
+		p.InitialBackoff = time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		attempts, err := s.runWithRetry(ctx, "job", func(ctx context.Context) error {
+			return ez.New(ez.EINTERNAL, "boom", nil)
+		}, p)
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	})
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"sync"
@@ -15,10 +16,22 @@ import (
 type (
 	// Job is a unit of work to be run by the Scheduler.
 	Job func(ctx context.Context)
+	// ErrorJob is a Job that can report failure, which makes it eligible for retries.
+	ErrorJob func(ctx context.Context) error
 	// scheduledJob couples a Job with its unique ID.
 	scheduledJob struct {
-		id  string
-		job Job
+		id   string
+		job  ErrorJob
+		opts *jobOptions
+	}
+	// cronJob couples a Job with its cron schedule and the next time it fires.
+	cronJob struct {
+		id       string
+		job      ErrorJob
+		opts     *jobOptions
+		schedule *CronSchedule
+		location *time.Location
+		next     time.Time
 	}
 )
 
@@ -26,46 +39,67 @@ type (
 // It also supports one-shot jobs and prevents concurrent runs of the same logical task (by ID).
 // For each distinct id, only one instance may run at the same time.
 type Scheduler struct {
-	tick            time.Duration          // e.g., 15m, 10m, 5m
-	granularity     int                    // minutes per tick
-	mu              sync.RWMutex           // protects slots
-	slots           map[int][]scheduledJob // key: minute-of-hour (0..59)
-	running         map[string]struct{}    // tracks in-flight job IDs
-	runMu           sync.Mutex             // protects running
-	wg              sync.WaitGroup         // tracks all spawned jobs
+	tick            time.Duration                    // e.g., 30s, 15m, 6h
+	cycle           time.Duration                    // hour or day, see cycleFor
+	mu              sync.RWMutex                     // protects slots
+	slots           map[time.Duration][]scheduledJob // key: offset within the cycle
+	crons           map[string]*cronJob              // key: job id, protected by mu
+	paused          map[string]struct{}              // paused job IDs, protected by mu
+	startCtx        context.Context                  // ctx given to Start, used by Trigger; protected by mu
+	cronWake        chan struct{}                    // wakes the cron loop when a cron job is added
+	running         map[string]*activeRun            // tracks in-flight job IDs
+	runMu           sync.Mutex                       // protects running
+	sem             chan struct{}                    // bounds concurrent runs, nil when unlimited
+	maxWaiting      int                              // runs allowed to wait for a free sem slot
+	wg              sync.WaitGroup                   // tracks all spawned jobs
 	log             logger.Logger
+	clock           Clock
 	shutdownTimeout time.Duration
 	jobTimeout      time.Duration
+	locker          Locker
+	leaseTTL        time.Duration
+	recorder        Recorder
+	jitter          Jitter
+	hooks           []Hooks
+	fireStore       FireStore
 	activeJobs      int64
+	waiting         int64
 	idledCh         chan struct{}
 }
 
 // New creates a Scheduler that fires every tick duration.
+// Ticks of up to an hour must divide the hour evenly (e.g. 30s, 5m, 15m) and longer
+// ticks must divide the day evenly (e.g. 2h, 6h, 12h).
 func New(tick time.Duration, opts ...Option) (*Scheduler, error) {
-	if tick <= 0 || tick%time.Minute != 0 {
-		return nil, ez.New(ez.EINVALID, "tick must be a positive multiple of 1 minute", nil)
+	if tick <= 0 || tick%time.Second != 0 {
+		return nil, ez.New(ez.EINVALID, "tick must be a positive multiple of 1 second", nil)
 	}
 
-	gran := int(tick / time.Minute)
-	if 60%gran != 0 {
-		errMsg := fmt.Sprintf("tick must divide 60 minutes evenly, got %dmin", gran)
+	cycle := cycleFor(tick)
+	if cycle%tick != 0 {
+		errMsg := fmt.Sprintf("tick must divide %s evenly, got %s", cycle, tick)
 		return nil, ez.New(ez.EINVALID, errMsg, nil)
 	}
 
 	s := &Scheduler{
 		tick:            tick,
-		granularity:     gran,
-		slots:           make(map[int][]scheduledJob),
-		running:         make(map[string]struct{}),
+		cycle:           cycle,
+		slots:           make(map[time.Duration][]scheduledJob),
+		crons:           make(map[string]*cronJob),
+		paused:          make(map[string]struct{}),
+		cronWake:        make(chan struct{}, 1),
+		running:         make(map[string]*activeRun),
 		log:             logger.Noop{},
+		clock:           realClock{},
 		shutdownTimeout: DefaultShutdownTimeout,
 		jobTimeout:      DefaultJobTimeout,
+		leaseTTL:        DefaultLeaseTTL,
 		idledCh:         make(chan struct{}, 1),
 	}
 
 	// initialize valid slots
-	for m := 0; m < 60; m += gran {
-		s.slots[m] = nil
+	for off := time.Duration(0); off < cycle; off += tick {
+		s.slots[off] = nil
 	}
 
 	// apply options
@@ -76,47 +110,130 @@ func New(tick time.Duration, opts ...Option) (*Scheduler, error) {
 	return s, nil
 }
 
-// Add registers a recurring job at the given slot (must be a multiple of granularity).
+// Add registers a recurring job at the given slot, in minutes into the cycle: minute-of-hour
+// for ticks of up to an hour, minute-of-day for longer ticks. The slot must fall on a tick.
 // id must be unique for each logical task; concurrent duplicates will be skipped.
-func (s *Scheduler) Add(id string, slot int, job Job) error {
+func (s *Scheduler) Add(id string, slot int, job Job, opts ...JobOption) error {
+	return s.AddErr(id, slot, job.errorJob(), opts...)
+}
+
+// AddErr is like Add for a job that returns an error, see JobRetry.
+func (s *Scheduler) AddErr(id string, slot int, job ErrorJob, opts ...JobOption) error {
+	return s.AddAtErr(id, time.Duration(slot)*time.Minute, job, opts...)
+}
+
+// AddAt registers a recurring job at the given offset into the cycle, e.g. 30*time.Second
+// for the second half of every minute with a 30s tick, or 6*time.Hour for 06:00 with a 6h tick.
+// The offset must fall on a tick.
+func (s *Scheduler) AddAt(id string, offset time.Duration, job Job, opts ...JobOption) error {
+	return s.AddAtErr(id, offset, job.errorJob(), opts...)
+}
+
+// AddAtErr is like AddAt for a job that returns an error, see JobRetry.
+func (s *Scheduler) AddAtErr(id string, offset time.Duration, job ErrorJob, opts ...JobOption) error {
 	if id == "" {
 		return ez.New(ez.EINVALID, "job id cannot be empty", nil)
 	}
 	if job == nil {
 		return ez.New(ez.EINVALID, "job cannot be nil", nil)
 	}
-	if slot < 0 || slot > 59 || slot%s.granularity != 0 {
-		errMsg := fmt.Sprintf("invalid slot %d for tick %dmin", slot, s.granularity)
+	if !s.validSlot(offset) {
+		errMsg := fmt.Sprintf("invalid slot %s for tick %s", offset, s.tick)
 		return ez.New(ez.EINVALID, errMsg, nil)
 	}
 
 	s.mu.Lock()
-	for _, sj := range s.slots[slot] {
+	if _, exists := s.crons[id]; exists {
+		s.mu.Unlock()
+		return ez.New(ez.ECONFLICT, "cron job with this id already exists", nil)
+	}
+	for _, sj := range s.slots[offset] {
 		if sj.id == id {
 			s.mu.Unlock()
-			return ez.New(ez.ECONFLICT, "job with this id already exists in this slot", nil)
+			return ez.New(ez.ECONFLICT, "job with this id already exists in this offset", nil)
 		}
 	}
 
-	s.slots[slot] = append(s.slots[slot], scheduledJob{id: id, job: job})
+	s.slots[offset] = append(s.slots[offset], scheduledJob{id: id, job: job, opts: newJobOptions(opts)})
 	s.mu.Unlock()
 	return nil
 }
 
 // AddMany registers the same job on multiple slots. IDs must be distinct per logical task.
-func (s *Scheduler) AddMany(id string, slots []int, job Job) error {
+func (s *Scheduler) AddMany(id string, slots []int, job Job, opts ...JobOption) error {
+	return s.AddManyErr(id, slots, job.errorJob(), opts...)
+}
+
+// AddManyErr is like AddMany for a job that returns an error, see JobRetry.
+func (s *Scheduler) AddManyErr(id string, slots []int, job ErrorJob, opts ...JobOption) error {
 	for _, sl := range slots {
-		if err := s.Add(id, sl, job); err != nil {
+		if err := s.AddErr(id, sl, job, opts...); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// AddCron registers a recurring job on a cron expression, e.g. "0 3 * * MON".
+// Both 5-field and 6-field (leading seconds) expressions are accepted, see ParseCron.
+// Cron jobs are evaluated in the JobTimezone option, the expression's CRON_TZ prefix,
+// or the local timezone, in that order, and share the same id de-duplication as slot jobs.
+func (s *Scheduler) AddCron(id, expr string, job Job, opts ...JobOption) error {
+	return s.AddCronErr(id, expr, job.errorJob(), opts...)
+}
+
+// AddCronErr is like AddCron for a job that returns an error, see JobRetry.
+func (s *Scheduler) AddCronErr(id, expr string, job ErrorJob, opts ...JobOption) error {
+	if id == "" {
+		return ez.New(ez.EINVALID, "job id cannot be empty", nil)
+	}
+	if job == nil {
+		return ez.New(ez.EINVALID, "job cannot be nil", nil)
+	}
+
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return ez.Wrap(err)
+	}
+
+	o := newJobOptions(opts)
+	loc := schedule.Location()
+	if o.timezone != "" {
+		loc, err = time.LoadLocation(o.timezone)
+		if err != nil {
+			errMsg := fmt.Sprintf("invalid timezone %q", o.timezone)
+			return ez.New(ez.EINVALID, errMsg, err)
+		}
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	cj := &cronJob{id: id, job: job, opts: o, schedule: schedule, location: loc}
+	cj.next = cj.nextAfter(s.clock.Now())
+
+	s.mu.Lock()
+	if _, exists := s.crons[id]; exists || s.hasSlotJob(id) {
+		s.mu.Unlock()
+		return ez.New(ez.ECONFLICT, "job with this id already exists", nil)
+	}
+	s.crons[id] = cj
+	s.mu.Unlock()
+
+	s.wakeCron()
+	return nil
+}
+
 // RunOnce fires a one-shot job immediately.
 // Returns true if the job was started, false if skipped because it's already running or invalid.
-func (s *Scheduler) RunOnce(ctx context.Context, id string, job Job) bool {
-	return s.spawnJob(ctx, id, job)
+// With a Locker configured the job may still be skipped if another replica holds its lease.
+func (s *Scheduler) RunOnce(ctx context.Context, id string, job Job, opts ...JobOption) bool {
+	return s.RunOnceErr(ctx, id, job.errorJob(), opts...)
+}
+
+// RunOnceErr is like RunOnce for a job that returns an error, see JobRetry.
+func (s *Scheduler) RunOnceErr(ctx context.Context, id string, job ErrorJob, opts ...JobOption) bool {
+	return s.spawnJob(ctx, id, job, s.clock.Now(), newJobOptions(opts))
 }
 
 // Idled returns a channel that receives a value whenever the scheduler drains all current jobs.
@@ -130,35 +247,58 @@ func (s *Scheduler) Active() int64 {
 	return atomic.LoadInt64(&s.activeJobs)
 }
 
+// Waiting returns the number of runs waiting for a free slot under WithMaxConcurrency.
+func (s *Scheduler) Waiting() int64 {
+	return atomic.LoadInt64(&s.waiting)
+}
+
 // Start blocks until ctx is canceled. It aligns to the next tick boundary,
-// then fires runJobs on each tick.
+// then fires runJobs on each tick. Jobs with a Jitter, including on the first
+// aligned tick, start after their offset.
 func (s *Scheduler) Start(ctx context.Context) {
-	// align to next slot
-	next := s.nextAligned(time.Now())
-	timer := time.NewTimer(time.Until(next))
-	defer timer.Stop()
+	s.mu.Lock()
+	s.startCtx = ctx
+	s.mu.Unlock()
 
-	select {
-	case <-ctx.Done():
+	// cron jobs run on their own loop, next to the tick loop
+	cronDone := make(chan struct{})
+	go func() {
+		defer close(cronDone)
+		s.runCron(ctx)
+	}()
+	defer func() {
+		<-cronDone
 		s.waitForJobs()
-		return
-	case <-timer.C:
-	}
+	}()
 
-	// run first batch immediately, then on each tick
-	s.runJobs(ctx, next)
-	ticker := time.NewTicker(s.tick)
-	defer ticker.Stop()
+	// handle runs missed while the scheduler was down
+	s.catchUp(ctx)
 
+	// sleep until each tick boundary; re-aligning every time keeps day cycles on
+	// the wall clock across DST changes
+	next := s.nextAligned(s.clock.Now())
 	for {
+		timer := s.clock.NewTimer(next.Sub(s.clock.Now()))
 		select {
 		case <-ctx.Done():
-			s.waitForJobs()
+			timer.Stop()
 			return
-		case now := <-ticker.C:
-			s.runJobs(ctx, now)
+		case <-timer.C():
 		}
+
+		s.runJobs(ctx, next)
+
+		// ticks missed while blocked (e.g. a suspended host) are dropped
+		next = s.nextAligned(latest(next, s.clock.Now()))
+	}
+}
+
+// latest returns the later of a and b.
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
 	}
+	return a
 }
 
 // waitForJobs blocks until all in-flight jobs finish or timeout.
@@ -169,9 +309,12 @@ func (s *Scheduler) waitForJobs() {
 		close(done)
 	}()
 
+	timer := s.clock.NewTimer(s.shutdownTimeout)
+	defer timer.Stop()
+
 	select {
 	case <-done:
-	case <-time.After(s.shutdownTimeout):
+	case <-timer.C():
 		s.log.Warn().Msg("Scheduler: timed out waiting for jobs to finish")
 	}
 }
@@ -181,97 +324,326 @@ func (s *Scheduler) runJobs(ctx context.Context, now time.Time) {
 	if ctx.Err() != nil {
 		return
 	}
-	slot := (now.Minute() / s.granularity) * s.granularity
+	slot := s.slotOf(now)
+	fireTime := s.atOffset(now, slot)
 
 	s.mu.RLock()
-	scheduledJobs := append([]scheduledJob(nil), s.slots[slot]...)
+	var scheduledJobs []scheduledJob
+	for _, sj := range s.slots[slot] {
+		if _, paused := s.paused[sj.id]; !paused {
+			scheduledJobs = append(scheduledJobs, sj)
+		}
+	}
 	s.mu.RUnlock()
 
 	for _, sj := range scheduledJobs {
-		wasSpawned := s.spawnJob(ctx, sj.id, sj.job)
-		if !wasSpawned {
-			s.log.Debug().Str("job_id", sj.id).Msg("Job already running or invalid, skipping")
+		s.dispatch(ctx, sj.id, sj.job, fireTime, sj.opts)
+	}
+}
+
+// runCron blocks until ctx is canceled, sleeping until the earliest cron fire time
+// and dispatching every cron job that is due.
+func (s *Scheduler) runCron(ctx context.Context) {
+	// recompute fire times so jobs added long before Start don't fire immediately
+	now := s.clock.Now()
+	s.mu.Lock()
+	for _, cj := range s.crons {
+		cj.next = cj.nextAfter(now)
+	}
+	s.mu.Unlock()
+
+	for {
+		var timer Timer
+		var timerC <-chan time.Time
+		if next := s.nextCron(); !next.IsZero() {
+			timer = s.clock.NewTimer(next.Sub(s.clock.Now()))
+			timerC = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.cronWake:
+		case now := <-timerC:
+			s.runCronJobs(ctx, now)
+		}
+
+		if timer != nil {
+			timer.Stop()
 		}
 	}
 }
 
-// spawnJob handles de-duplicating by id, tracking, panic recovery, and wg.
-// Returns true if the job was started, false if skipped.
-func (s *Scheduler) spawnJob(ctx context.Context, id string, job Job) bool {
-	if id == "" || job == nil || ctx.Err() != nil {
-		return false
+// nextCron returns the earliest fire time among all cron jobs, or zero if there are none.
+func (s *Scheduler) nextCron() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var next time.Time
+	for _, cj := range s.crons {
+		if cj.next.IsZero() {
+			continue
+		}
+		if next.IsZero() || cj.next.Before(next) {
+			next = cj.next
+		}
 	}
+	return next
+}
 
-	// prevent duplicate concurrent runs
-	s.runMu.Lock()
-	if _, busy := s.running[id]; busy {
-		s.runMu.Unlock()
-		return false
+// runCronJobs dispatches all cron jobs due at now and schedules their next fire time.
+func (s *Scheduler) runCronJobs(ctx context.Context, now time.Time) {
+	if ctx.Err() != nil {
+		return
 	}
-	s.running[id] = struct{}{}
-	s.runMu.Unlock()
 
-	atomic.AddInt64(&s.activeJobs, 1)
-	s.wg.Add(1)
-	start := time.Now()
+	type dueJob struct {
+		cj       *cronJob
+		fireTime time.Time
+	}
+
+	var due []dueJob
+	s.mu.Lock()
+	for _, cj := range s.crons {
+		if cj.next.IsZero() || cj.next.After(now) {
+			continue
+		}
+		// paused jobs still move on, so resuming doesn't fire a stale time
+		if _, paused := s.paused[cj.id]; !paused {
+			due = append(due, dueJob{cj: cj, fireTime: cj.next})
+		}
+		cj.next = cj.nextAfter(now)
+	}
+	s.mu.Unlock()
+
+	for _, d := range due {
+		s.dispatch(ctx, d.cj.id, d.cj.job, d.fireTime, d.cj.opts)
+	}
+}
 
+// wakeCron makes the cron loop recompute its next fire time.
+func (s *Scheduler) wakeCron() {
+	select {
+	case s.cronWake <- struct{}{}:
+	default:
+	}
+}
+
+// nextAfter returns the next fire time of the cron job after t, in the job's timezone.
+func (cj *cronJob) nextAfter(t time.Time) time.Time {
+	return cj.schedule.nextIn(t, cj.location)
+}
+
+// spawnJob handles de-duplicating by id, tracking, panic recovery, and wg.
+// fireTime identifies the scheduled run across replicas when a Locker is configured.
+// Returns true if the job was started or queued by its OverlapPolicy, false if skipped.
+func (s *Scheduler) spawnJob(ctx context.Context, id string, job ErrorJob, fireTime time.Time, opts *jobOptions) bool {
+	if job == nil {
+		return false
+	}
+	return s.spawnRuns(id, []pendingRun{{ctx: ctx, job: job, fireTime: fireTime, opts: opts}})
+}
+
+// execute runs a job for fireTime in the calling goroutine: it applies the
+// job timeout, acquires the lease, retries, recovers panics and records the run.
+// jitter is the delay the run was started with, for logging.
+func (s *Scheduler) execute(ctx context.Context, id string, job ErrorJob, fireTime time.Time, opts *jobOptions, jitter time.Duration) {
 	// ensure a default timeout if none is set
 	jobCtx := ctx
 	var cancel context.CancelFunc
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		jobCtx, cancel = context.WithTimeout(ctx, s.jobTimeout)
 	} else {
-		cancel = func() {}
+		jobCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	run := Run{JobID: id, FireTime: fireTime}
+	var runErr error
+	defer func() {
+		r := recover()
+		if !run.StartedAt.IsZero() {
+			run.EndedAt = s.clock.Now()
+			run.Duration = run.EndedAt.Sub(run.StartedAt)
+		}
+
+		// panic recovery
+		if r != nil {
+			stack := debug.Stack()
+			s.log.Error().
+				Str("job_id", id).
+				Time("start", run.StartedAt).
+				Dur("duration", run.Duration).
+				Any("panic", r).
+				Bytes("stack", stack).
+				Msg("Scheduler job panic")
+			run.Status = RunStatusPanic
+			run.Error = fmt.Sprint(r)
+			run.PanicStack = string(stack)
+			s.callHooks(id, func(h Hooks) {
+				if h.OnPanic != nil {
+					h.OnPanic(jobCtx, run, r, stack)
+				}
+			})
+		}
+
+		// runs skipped before starting (e.g. lease held elsewhere) are not recorded
+		if run.StartedAt.IsZero() {
+			return
+		}
+		if runErr != nil {
+			s.callHooks(id, func(h Hooks) {
+				if h.OnError != nil {
+					h.OnError(jobCtx, run, runErr)
+				}
+			})
+		}
+		s.callHooks(id, func(h Hooks) {
+			if h.OnFinish != nil {
+				h.OnFinish(jobCtx, run)
+			}
+		})
+		s.recordRun(run)
+	}()
+
+	if s.locker != nil {
+		releaseLease, ok := s.acquireLease(jobCtx, cancel, id, fireTime)
+		if !ok {
+			return
+		}
+		defer releaseLease()
 	}
 
+	run.StartedAt = s.clock.Now()
+	s.saveLastFire(id, fireTime)
+	s.log.Info().Str("job_id", id).Time("start", run.StartedAt).Dur("jitter", jitter).Msg("Scheduler job started")
+	s.callHooks(id, func(h Hooks) {
+		if h.OnStart != nil {
+			h.OnStart(jobCtx, run)
+		}
+	})
+	defer func() {
+		end := s.clock.Now()
+		s.log.Info().Str("job_id", id).Time("end", end).Dur("duration", end.Sub(run.StartedAt)).Msg("Scheduler job finished")
+	}()
+	attempts, err := s.runWithRetry(jobCtx, id, job, opts.retry)
+	run.Attempts = attempts
+
+	switch {
+	case errors.Is(jobCtx.Err(), context.DeadlineExceeded):
+		run.Status = RunStatusTimeout
+		run.Error = jobCtx.Err().Error()
+		runErr = jobCtx.Err()
+	case errors.Is(context.Cause(jobCtx), errReplaced):
+		run.Status = RunStatusCanceled
+		run.Error = errReplaced.Error()
+	case err != nil:
+		run.Status = RunStatusError
+		run.Error = err.Error()
+		runErr = err
+		s.log.Error().Str("job_id", id).Int("attempts", attempts).Err(err).Msg("Scheduler job failed")
+	default:
+		run.Status = RunStatusOK
+	}
+}
+
+// recordRun hands the run to the Recorder in the background, so slow storage
+// never delays dispatching. Shutdown waits for pending records like it does for jobs.
+func (s *Scheduler) recordRun(run Run) {
+	if s.recorder == nil {
+		return
+	}
+
+	s.wg.Add(1)
 	go func() {
-		s.log.Info().Str("job_id", id).Time("start", start).Msg("Scheduler job started")
 		defer s.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultRecordTimeout)
 		defer cancel()
-		defer func() {
-			// panic recovery
-			if r := recover(); r != nil {
-				s.log.Error().
-					Time("start", start).
-					Dur("duration", time.Since(start)).
-					Any("panic", r).
-					Bytes("stack", debug.Stack()).
-					Msg("Scheduler job panic")
-			}
-			// cleanup running flag
-			s.runMu.Lock()
-			delete(s.running, id)
-			s.runMu.Unlock()
-			s.log.Info().Str("job_id", id).Time("end", time.Now()).Dur("duration", time.Since(start)).Msg("Scheduler job finished")
-			if atomic.AddInt64(&s.activeJobs, -1) == 0 {
-				if ch := s.idledCh; ch != nil {
-					select {
-					case ch <- struct{}{}:
-					default:
-					}
+		if err := s.recorder.Record(ctx, run); err != nil {
+			s.log.Warn().Str("job_id", run.JobID).Str("status", string(run.Status)).Err(err).Msg("Scheduler could not record job run")
+		}
+	}()
+}
+
+// acquireLease claims the job's fire time on the Locker and keeps renewing it until released.
+// If a renewal fails the job context is canceled, since another replica may take over.
+// Returns false if the lease could not be acquired and the job must be skipped.
+func (s *Scheduler) acquireLease(ctx context.Context, cancel context.CancelFunc, id string, fireTime time.Time) (func(), bool) {
+	lease, ok, err := s.locker.Acquire(ctx, id, fireTime, s.leaseTTL)
+	if err != nil {
+		s.log.Error().Str("job_id", id).Time("fire_time", fireTime).Err(err).Msg("Scheduler could not acquire job lease, skipping")
+		return nil, false
+	}
+	if !ok {
+		s.log.Debug().Str("job_id", id).Time("fire_time", fireTime).Msg("Job claimed by another replica, skipping")
+		return nil, false
+	}
+
+	stop := make(chan struct{})
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		ticker := s.clock.NewTicker(s.leaseTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				if err := lease.Renew(ctx, s.leaseTTL); err != nil {
+					s.log.Warn().Str("job_id", id).Err(err).Msg("Scheduler lost job lease, canceling job")
+					cancel()
+					return
 				}
 			}
-		}()
-		job(jobCtx)
+		}
 	}()
 
-	return true
+	release := func() {
+		close(stop)
+		<-renewed
+
+		// the job context may already be done, so release on a fresh one
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), s.leaseTTL)
+		defer releaseCancel()
+		if err := lease.Release(releaseCtx); err != nil {
+			s.log.Warn().Str("job_id", id).Err(err).Msg("Scheduler could not release job lease")
+		}
+	}
+
+	return release, true
 }
 
-// nextAligned returns the next time aligned to s.tick (never returns t itself).
+// errorJob adapts a Job that can't fail to an ErrorJob. A nil Job stays nil.
+func (j Job) errorJob() ErrorJob {
+	if j == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		j(ctx)
+		return nil
+	}
+}
+
+// nextAligned returns the next tick boundary after t (never returns t itself).
 func (s *Scheduler) nextAligned(t time.Time) time.Time {
 	t = t.Truncate(time.Second)
-	hourStart := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
-	elapsed := t.Sub(hourStart)
-	rem := elapsed % s.tick
+	off := s.slotOf(t) + s.tick
+	next := s.atOffset(t, off)
 
-	var wait time.Duration
-	if rem == 0 {
-		wait = s.tick
-	} else {
-		wait = s.tick - rem
+	// wall-clock day cycles can land on or before t around DST changes
+	for !next.After(t) {
+		off += s.tick
+		next = s.atOffset(t, off)
 	}
-	return t.Add(wait)
+	return next
 }
 
 func ShouldRunLocalHour(tz string, hour int) bool {
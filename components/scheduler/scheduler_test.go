@@ -0,0 +1,297 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/logger/logtest"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/compose/components/scheduler/schedulertest"
+)
+
+const waitTimeout = 5 * time.Second
+
+type harness struct {
+	s        *scheduler.Scheduler
+	clock    *schedulertest.Clock
+	recorder *schedulertest.Recorder
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newHarness(t *testing.T, tick time.Duration, now time.Time, opts ...scheduler.Option) *harness {
+	t.Helper()
+
+	h := &harness{
+		clock:    schedulertest.NewClock(now),
+		recorder: schedulertest.NewRecorder(),
+		done:     make(chan struct{}),
+	}
+
+	opts = append([]scheduler.Option{scheduler.WithClock(h.clock), scheduler.WithRecorder(h.recorder)}, opts...)
+	s, err := scheduler.New(tick, opts...)
+	require.NoError(t, err)
+	h.s = s
+
+	return h
+}
+
+func (h *harness) start(t *testing.T) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go func() {
+		defer close(h.done)
+		h.s.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-h.done
+	})
+
+	// wait for the alignment timer
+	h.clock.BlockUntil(1)
+}
+
+func noop(context.Context) {}
+
+func TestStartAlignsToNextTick(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tick     time.Duration
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			"mid slot",
+			15 * time.Minute,
+			time.Date(2024, 1, 10, 10, 7, 30, 0, time.UTC),
+			time.Date(2024, 1, 10, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			"exactly on a boundary waits a full tick",
+			15 * time.Minute,
+			time.Date(2024, 1, 10, 10, 15, 0, 0, time.UTC),
+			time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			"sub second past a boundary",
+			5 * time.Minute,
+			time.Date(2024, 1, 10, 10, 55, 0, 500, time.UTC),
+			time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			"hourly tick rolls over the day",
+			time.Hour,
+			time.Date(2024, 1, 10, 23, 59, 59, 0, time.UTC),
+			time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t, tc.tick, tc.now)
+			require.NoError(t, h.s.Add("job", tc.expected.Minute(), noop))
+			h.start(t)
+
+			h.clock.Set(tc.expected.Add(-time.Second))
+			require.Empty(t, h.recorder.Runs())
+
+			h.clock.Set(tc.expected)
+			runs, ok := h.recorder.WaitRuns(1, waitTimeout)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, runs[0].FireTime)
+		})
+	}
+}
+
+func TestStartFiresJobsInTheirSlots(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC))
+	require.NoError(t, h.s.Add("hourly", 0, noop))
+	require.NoError(t, h.s.AddMany("half", []int{0, 30}, noop))
+	require.NoError(t, h.s.Add("quarter", 15, noop))
+	h.start(t)
+
+	// 10:00 is reached through the alignment timer, then a timer per tick takes over
+	h.clock.Set(time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC))
+	_, ok := h.recorder.WaitRuns(2, waitTimeout)
+	require.True(t, ok)
+	h.clock.BlockUntil(1)
+
+	h.clock.Advance(15 * time.Minute)
+	_, ok = h.recorder.WaitRuns(3, waitTimeout)
+	require.True(t, ok)
+
+	h.clock.Advance(15 * time.Minute)
+	_, ok = h.recorder.WaitRuns(4, waitTimeout)
+	require.True(t, ok)
+
+	fired := h.recorder.Fired()
+	require.ElementsMatch(t, []string{"hourly", "half"}, fired[time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)])
+	require.ElementsMatch(t, []string{"quarter"}, fired[time.Date(2024, 1, 10, 10, 15, 0, 0, time.UTC)])
+	require.ElementsMatch(t, []string{"half"}, fired[time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)])
+}
+
+func TestStartFiresCronJobs(t *testing.T) {
+	h := newHarness(t, time.Hour, time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC)) // Sunday
+	require.NoError(t, h.s.AddCron("weekly", "0 3 * * MON", noop, scheduler.JobTimezone("UTC")))
+	h.start(t)
+
+	// alignment timer and cron timer
+	h.clock.BlockUntil(2)
+	h.clock.Set(time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC))
+
+	_, ok := h.recorder.WaitRuns(1, waitTimeout)
+	require.True(t, ok)
+	require.Equal(t, []time.Time{time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC)}, h.recorder.FireTimes("weekly"))
+}
+
+func TestShutdownDrainsRunningJobs(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC),
+		scheduler.WithShutdownTimeout(time.Minute),
+	)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var once sync.Once
+	require.NoError(t, h.s.Add("slow", 0, func(ctx context.Context) {
+		once.Do(func() { close(started) })
+		<-finish
+	}))
+	h.start(t)
+
+	h.clock.Advance(10 * time.Minute)
+	<-started
+
+	h.cancel()
+	select {
+	case <-h.done:
+		t.Fatal("Start returned while a job was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(finish)
+	select {
+	case <-h.done:
+	case <-time.After(waitTimeout):
+		t.Fatal("Start did not return after the job finished")
+	}
+
+	runs, ok := h.recorder.WaitRuns(1, waitTimeout)
+	require.True(t, ok)
+	require.Equal(t, scheduler.RunStatusOK, runs[0].Status)
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC),
+		scheduler.WithShutdownTimeout(time.Minute),
+	)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	defer close(finish)
+	require.NoError(t, h.s.Add("stuck", 0, func(ctx context.Context) {
+		close(started)
+		<-finish
+	}))
+	h.start(t)
+
+	h.clock.Advance(10 * time.Minute)
+	<-started
+
+	h.cancel()
+
+	// keep moving the clock until the shutdown timer is created and fires
+	deadline := time.After(waitTimeout)
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-deadline:
+			t.Fatal("Start did not return after the shutdown timeout")
+		case <-time.After(10 * time.Millisecond):
+			h.clock.Advance(time.Minute)
+		}
+	}
+}
+
+func TestRunOnceSkipsRunningJob(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC))
+
+	finish := make(chan struct{})
+	ctx := context.Background()
+	require.True(t, h.s.RunOnce(ctx, "export", func(ctx context.Context) { <-finish }))
+	require.False(t, h.s.RunOnce(ctx, "export", noop))
+	close(finish)
+
+	runs, ok := h.recorder.WaitRuns(2, waitTimeout)
+	require.True(t, ok)
+
+	statuses := []scheduler.RunStatus{runs[0].Status, runs[1].Status}
+	require.ElementsMatch(t, []scheduler.RunStatus{scheduler.RunStatusSkipped, scheduler.RunStatusOK}, statuses)
+}
+
+func TestStartAppliesJitter(t *testing.T) {
+	h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC),
+		scheduler.WithJitter(scheduler.Jitter{Max: time.Minute, Mode: scheduler.JitterHash}),
+	)
+	require.NoError(t, h.s.Add("report", 0, noop))
+	h.start(t)
+
+	fireTime := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	h.clock.Set(fireTime)
+
+	// next tick timer and jitter timer
+	h.clock.BlockUntil(2)
+	require.Empty(t, h.recorder.Runs())
+
+	h.clock.Advance(time.Minute)
+	runs, ok := h.recorder.WaitRuns(1, waitTimeout)
+	require.True(t, ok)
+	require.Equal(t, fireTime, runs[0].FireTime)
+	require.True(t, runs[0].StartedAt.After(fireTime))
+}
+
+func TestJitterWaitChecksJob(t *testing.T) {
+	testCases := []struct {
+		name   string
+		change func(s *scheduler.Scheduler) error
+	}{
+		{"paused", func(s *scheduler.Scheduler) error { return s.Pause("report") }},
+		{"removed", func(s *scheduler.Scheduler) error { return s.Remove("report") }},
+		{"registered again", func(s *scheduler.Scheduler) error {
+			if err := s.Remove("report"); err != nil {
+				return err
+			}
+			return s.Add("report", 30, noop)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := logtest.New()
+			h := newHarness(t, 15*time.Minute, time.Date(2024, 1, 10, 9, 50, 0, 0, time.UTC),
+				scheduler.WithLogger(log),
+				scheduler.WithJitter(scheduler.Jitter{Max: time.Minute, Mode: scheduler.JitterHash}),
+			)
+			require.NoError(t, h.s.Add("report", 0, noop))
+			h.start(t)
+
+			h.clock.Set(time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC))
+
+			// next tick timer and jitter timer
+			h.clock.BlockUntil(2)
+			require.NoError(t, tc.change(h.s))
+
+			h.clock.Advance(time.Minute)
+			require.Eventually(t, func() bool {
+				return log.HasEntry(logtest.LevelDebug, "Job paused or removed during its jitter, skipping", "job_id", "report")
+			}, waitTimeout, time.Millisecond, log)
+			require.Empty(t, h.recorder.Runs())
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package schedulerhttp exposes scheduler.Scheduler job management over an Echo route group.
+package schedulerhttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vanclief/compose/components/rest/handler"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/ez"
+)
+
+// Job describes a registered job, see scheduler.JobInfo.
+type Job struct {
+	ID       string            `json:"id"`
+	Kind     scheduler.JobKind `json:"kind"`
+	Slots    []string          `json:"slots,omitempty"`    // offsets within the cycle like "1h30m0s", for slot jobs
+	Cron     string            `json:"cron,omitempty"`     // cron expression, for cron jobs
+	Timezone string            `json:"timezone,omitempty"` // evaluation timezone, for cron jobs
+	NextRun  time.Time         `json:"next_run"`           // zero if the job never fires again
+	Paused   bool              `json:"paused"`
+	Running  bool              `json:"running"`
+}
+
+// newJob converts info, time.Duration would be encoded as nanoseconds.
+func newJob(info scheduler.JobInfo) Job {
+	job := Job{
+		ID:       info.ID,
+		Kind:     info.Kind,
+		Cron:     info.Cron,
+		Timezone: info.Timezone,
+		NextRun:  info.NextRun,
+		Paused:   info.Paused,
+		Running:  info.Running,
+	}
+
+	for _, slot := range info.Slots {
+		job.Slots = append(job.Slots, slot.String())
+	}
+
+	return job
+}
+
+// TriggerResponse is returned when a job is run manually.
+type TriggerResponse struct {
+	ID      string `json:"id"`
+	Started bool   `json:"started"` // false if the job was already running
+}
+
+// Register mounts the job management routes on g:
+//
+//	GET    /            list jobs
+//	GET    /:id         get a job
+//	POST   /:id/pause   pause a job
+//	POST   /:id/resume  resume a job
+//	POST   /:id/run     run a job now
+//	DELETE /:id         remove a job
+//
+// The routes perform no authentication; protect g with the app's middleware.
+func Register(g *echo.Group, s *scheduler.Scheduler) {
+	r := &routes{s: s}
+
+	g.GET("", r.list)
+	g.GET("/:id", r.get)
+	g.POST("/:id/pause", r.pause)
+	g.POST("/:id/resume", r.resume)
+	g.POST("/:id/run", r.run)
+	g.DELETE("/:id", r.remove)
+}
+
+type routes struct {
+	s *scheduler.Scheduler
+}
+
+func (r *routes) list(c echo.Context) error {
+	infos := r.s.Jobs()
+
+	jobs := make([]Job, len(infos))
+	for i, info := range infos {
+		jobs[i] = newJob(info)
+	}
+
+	return c.JSON(http.StatusOK, jobs)
+}
+
+func (r *routes) get(c echo.Context) error {
+	info, err := r.s.Job(c.Param("id"))
+	if err != nil {
+		return manageError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newJob(info))
+}
+
+func (r *routes) pause(c echo.Context) error {
+	id := c.Param("id")
+	if err := r.s.Pause(id); err != nil {
+		return manageError(c, err)
+	}
+
+	return r.get(c)
+}
+
+func (r *routes) resume(c echo.Context) error {
+	id := c.Param("id")
+	if err := r.s.Resume(id); err != nil {
+		return manageError(c, err)
+	}
+
+	return r.get(c)
+}
+
+func (r *routes) run(c echo.Context) error {
+	id := c.Param("id")
+	started, err := r.s.Trigger(id)
+	if err != nil {
+		return manageError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, TriggerResponse{ID: id, Started: started})
+}
+
+func (r *routes) remove(c echo.Context) error {
+	if err := r.s.Remove(c.Param("id")); err != nil {
+		return manageError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// manageError writes err with the same body the rest handler uses.
+func manageError(c echo.Context, err error) error {
+	stdErr := handler.StandardError{Code: ez.ErrorCode(err), Message: ez.ErrorMessage(err)}
+	return c.JSON(ez.ErrorToHTTPStatus(err), handler.ErrorResponse{Error: stdErr})
+}
@@ -0,0 +1,47 @@
+package schedulerhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vanclief/compose/components/scheduler"
+)
+
+func TestJobSlots(t *testing.T) {
+	s, err := scheduler.New(30 * time.Second)
+	require.NoError(t, err)
+
+	noop := func(ctx context.Context) {}
+	require.NoError(t, s.AddMany("report", []int{0, 30}, noop))
+	require.NoError(t, s.AddAt("sync", 90*time.Second, noop))
+	require.NoError(t, s.AddCron("cleanup", "0 3 * * *", noop))
+
+	e := echo.New()
+	Register(e.Group("/jobs"), s)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var jobs []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobs))
+	require.Len(t, jobs, 3)
+	require.Equal(t, "cleanup", jobs[0]["id"])
+	require.NotContains(t, jobs[0], "slots")
+	require.Equal(t, []any{"0s", "30m0s"}, jobs[1]["slots"])
+	require.Equal(t, []any{"1m30s"}, jobs[2]["slots"])
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/sync", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var job Job
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	require.Equal(t, []string{"1m30s"}, job.Slots)
+}
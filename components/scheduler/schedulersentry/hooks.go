@@ -0,0 +1,80 @@
+// Package schedulersentry reports failed and panicking scheduler.Scheduler jobs to Sentry.
+package schedulersentry
+
+import (
+	"context"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/vanclief/compose/components/scheduler"
+	"github.com/vanclief/ez"
+)
+
+// Hooks returns scheduler hooks that capture job errors and panics on hub,
+// tagged with the job ID, slot and duration. Nil hub => the hub in the job
+// context, or the current hub.
+//
+//	s, err := scheduler.New(15*time.Minute, scheduler.WithHooks(schedulersentry.Hooks(nil)))
+func Hooks(hub *sentry.Hub) scheduler.Hooks {
+	return scheduler.Hooks{
+		OnError: func(ctx context.Context, run scheduler.Run, err error) {
+			withScope(ctx, hub, run, func(hub *sentry.Hub, scope *sentry.Scope) {
+				scope.SetLevel(sentry.LevelError)
+				breadcrumbStacktrace(scope, err)
+				hub.CaptureException(err)
+			})
+		},
+		OnPanic: func(ctx context.Context, run scheduler.Run, recovered any, stack []byte) {
+			withScope(ctx, hub, run, func(hub *sentry.Hub, scope *sentry.Scope) {
+				scope.SetLevel(sentry.LevelFatal)
+				scope.SetExtra("stack", string(stack))
+				hub.RecoverWithContext(ctx, recovered)
+			})
+		},
+	}
+}
+
+// withScope resolves the hub and calls capture with a scope describing run.
+func withScope(ctx context.Context, hub *sentry.Hub, run scheduler.Run, capture func(*sentry.Hub, *sentry.Scope)) {
+	if hub == nil {
+		hub = sentry.GetHubFromContext(ctx)
+	}
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	if hub.Client() == nil {
+		// Sentry is not configured, nothing to do
+		return
+	}
+
+	// jobs run concurrently, so each capture gets its own hub and scope
+	hub = hub.Clone()
+	scope := hub.Scope()
+	scope.SetTag("job_id", run.JobID)
+	scope.SetTag("slot", run.FireTime.Format(time.TimeOnly))
+	scope.SetTag("duration", run.Duration.String())
+	scope.SetTag("status", string(run.Status))
+	scope.SetExtra("fire_time", run.FireTime)
+	scope.SetExtra("attempts", run.Attempts)
+
+	capture(hub, scope)
+}
+
+// breadcrumbStacktrace adds one breadcrumb per wrapped ez.Error, outermost first.
+func breadcrumbStacktrace(scope *sentry.Scope, err error) {
+	if err == nil {
+		return
+	} else if e, ok := err.(*ez.Error); ok {
+		scope.AddBreadcrumb(&sentry.Breadcrumb{
+			Category: e.Code,
+			Message:  e.String(),
+			Level:    sentry.LevelError,
+		}, 10)
+		breadcrumbStacktrace(scope, e.Err)
+	} else {
+		scope.AddBreadcrumb(&sentry.Breadcrumb{
+			Message: err.Error(),
+			Level:   sentry.LevelError,
+		}, 10)
+	}
+}
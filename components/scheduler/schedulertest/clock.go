@@ -0,0 +1,163 @@
+// Package schedulertest provides a fake Clock and a capturing Recorder to test
+// scheduler wiring without waiting on real time.
+package schedulertest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vanclief/compose/components/scheduler"
+)
+
+// Clock is a scheduler.Clock that only moves when Advance or Set is called.
+// Timers and tickers fire synchronously during Advance, in chronological order.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+	changed chan struct{} // closed and replaced whenever waiters change
+}
+
+// waiter is a pending fake timer or ticker.
+type waiter struct {
+	clock  *Clock
+	at     time.Time
+	period time.Duration // zero for timers
+	c      chan time.Time
+}
+
+// NewClock returns a Clock set to now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now, changed: make(chan struct{})}
+}
+
+// Now implements scheduler.Clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer implements scheduler.Clock.
+func (c *Clock) NewTimer(d time.Duration) scheduler.Timer {
+	return c.addWaiter(d, 0)
+}
+
+// NewTicker implements scheduler.Clock.
+func (c *Clock) NewTicker(d time.Duration) scheduler.Ticker {
+	if d <= 0 {
+		panic("schedulertest: non-positive interval for NewTicker")
+	}
+	return tickerWaiter{c.addWaiter(d, d)}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker due on the way.
+func (c *Clock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, firing every timer and ticker due up to t.
+// Moving the clock backwards fires nothing.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+		if len(c.waiters) == 0 || c.waiters[0].at.After(t) {
+			break
+		}
+
+		w := c.waiters[0]
+		if w.at.After(c.now) {
+			c.now = w.at
+		}
+
+		// like the time package, drop ticks nobody is reading
+		select {
+		case w.c <- c.now:
+		default:
+		}
+
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			c.removeLocked(w)
+		}
+	}
+
+	c.now = t
+}
+
+// BlockUntil blocks until at least n timers and tickers are waiting on the clock.
+// Use it to make sure the scheduler is parked before calling Advance.
+func (c *Clock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		count, changed := len(c.waiters), c.changed
+		c.mu.Unlock()
+
+		if count >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// Waiters returns the number of active timers and tickers.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func (c *Clock) addWaiter(d, period time.Duration) *waiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &waiter{clock: c, at: c.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	c.notifyLocked()
+	return w
+}
+
+// removeLocked drops w from the waiters and reports whether it was still active.
+func (c *Clock) removeLocked(w *waiter) bool {
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.notifyLocked()
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Clock) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// C implements scheduler.Timer and scheduler.Ticker.
+func (w *waiter) C() <-chan time.Time {
+	return w.c
+}
+
+// Stop implements scheduler.Timer. It reports whether the timer was still pending.
+func (w *waiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.removeLocked(w)
+}
+
+// tickerWaiter adapts waiter to scheduler.Ticker, whose Stop returns nothing.
+type tickerWaiter struct{ *waiter }
+
+func (t tickerWaiter) Stop() { t.waiter.Stop() }
+
+var (
+	_ scheduler.Clock  = (*Clock)(nil)
+	_ scheduler.Timer  = (*waiter)(nil)
+	_ scheduler.Ticker = tickerWaiter{}
+)
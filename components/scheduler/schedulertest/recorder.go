@@ -0,0 +1,93 @@
+package schedulertest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vanclief/compose/components/scheduler"
+)
+
+// Recorder is a scheduler.Recorder that keeps every run in memory.
+type Recorder struct {
+	mu      sync.Mutex
+	runs    []scheduler.Run
+	changed chan struct{} // closed and replaced on every Record
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{changed: make(chan struct{})}
+}
+
+// Record implements scheduler.Recorder.
+func (r *Recorder) Record(_ context.Context, run scheduler.Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runs = append(r.runs, run)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	return nil
+}
+
+// Runs returns a copy of every recorded run, in the order they were recorded.
+func (r *Recorder) Runs() []scheduler.Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]scheduler.Run(nil), r.runs...)
+}
+
+// WaitRuns blocks until at least n runs were recorded or timeout (real time) elapses.
+// It returns the recorded runs and whether n was reached.
+func (r *Recorder) WaitRuns(n int, timeout time.Duration) ([]scheduler.Run, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		r.mu.Lock()
+		runs, changed := append([]scheduler.Run(nil), r.runs...), r.changed
+		r.mu.Unlock()
+
+		if len(runs) >= n {
+			return runs, true
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return runs, false
+		}
+	}
+}
+
+// Fired returns the job IDs that ran (skipped runs excluded) for each fire time.
+func (r *Recorder) Fired() map[time.Time][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fired := make(map[time.Time][]string)
+	for _, run := range r.runs {
+		if run.Status == scheduler.RunStatusSkipped {
+			continue
+		}
+		fired[run.FireTime] = append(fired[run.FireTime], run.JobID)
+	}
+	return fired
+}
+
+// FireTimes returns the fire times jobID ran at (skipped runs excluded), in the order they were recorded.
+func (r *Recorder) FireTimes(jobID string) []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var times []time.Time
+	for _, run := range r.runs {
+		if run.JobID == jobID && run.Status != scheduler.RunStatusSkipped {
+			times = append(times, run.FireTime)
+		}
+	}
+	return times
+}
+
+var _ scheduler.Recorder = (*Recorder)(nil)
@@ -4,11 +4,26 @@ import (
 	"context"
 
 	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/logger"
 	"github.com/vanclief/ez"
 )
 
 type DB struct {
 	*bun.DB
+	log logger.Logger
+}
+
+// SetLogger sets the Logger the DB reports migrations to.
+func (db *DB) SetLogger(log logger.Logger) {
+	db.log = log
+}
+
+// Logger returns the Logger set with SetLogger or WithLogger, logger.Default if none.
+func (db *DB) Logger() logger.Logger {
+	if db.log == nil {
+		return logger.Default()
+	}
+	return db.log
 }
 
 // CreateTables - Creates the database schema if it doesn't already exist
@@ -3,7 +3,6 @@ package relational
 import (
 	"context"
 
-	"github.com/rs/zerolog/log"
 	"github.com/uptrace/bun/migrate"
 	"github.com/vanclief/ez"
 )
@@ -11,6 +10,7 @@ import (
 // RunMigrations - Executes all pending migrations
 func (db *DB) RunMigrations(migrations *migrate.Migrations) error {
 	ctx := context.Background()
+	log := db.Logger()
 
 	if len(migrations.Sorted()) == 0 {
 		log.Info().Msg("No pending migrations to run")
@@ -53,6 +53,7 @@ func (db *DB) RunMigrations(migrations *migrate.Migrations) error {
 // RollbackLastMigration - Rollbacks the last migration
 func (db *DB) RollbackLastMigration(migrations *migrate.Migrations) error {
 	ctx := context.Background()
+	log := db.Logger()
 
 	migrator := migrate.NewMigrator(db.DB, migrations)
 	err := migrator.Init(ctx)
@@ -1,5 +1,7 @@
 package relational
 
+import "github.com/vanclief/compose/components/logger"
+
 type Option func(db *DB) error
 
 func WithExtensions(extensions []string) Option {
@@ -13,3 +15,10 @@ func WithRegistrableModels(models []interface{}) Option {
 		return db.RegisterModels(models)
 	}
 }
+
+func WithLogger(log logger.Logger) Option {
+	return func(db *DB) error {
+		db.SetLogger(log)
+		return nil
+	}
+}
@@ -1,5 +1,7 @@
 package postgres
 
+import "github.com/vanclief/compose/components/logger"
+
 const (
 	DEFAULT_DIAL_TIMEOUT      = 10 * 1000 // 10 seconds in milliseconds
 	DEFAULT_READ_TIMEOUT      = 30 * 1000 // 30 seconds in milliseconds
@@ -18,4 +20,14 @@ type ConnectionConfig struct {
 	ReadTimeout      int    `mapstructure:"readTimeout"`
 	WriteTimeout     int    `mapstructure:"writeTimeout"`
 	StatementTimeout int    `mapstructure:"statementTimeout"`
+
+	// Logger receives the connection logs and is set on the connected DB. Defaults to logger.Default.
+	Logger logger.Logger `mapstructure:"-"`
+}
+
+func (cfg *ConnectionConfig) logger() logger.Logger {
+	if cfg.Logger == nil {
+		return logger.Default()
+	}
+	return cfg.Logger
 }
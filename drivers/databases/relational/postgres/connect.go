@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/rs/zerolog/log"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
@@ -43,6 +42,7 @@ func ConnectToDatabase(cfg *ConnectionConfig) (*relational.DB, error) {
 		writeTimeout = cfg.WriteTimeout
 	}
 
+	log := cfg.logger()
 	log.Info().
 		Str("Host", cfg.Host).
 		Str("Username", cfg.Username).
@@ -79,5 +79,8 @@ func ConnectToDatabase(cfg *ConnectionConfig) (*relational.DB, error) {
 		return nil, ez.Wrap(err)
 	}
 
-	return &relational.DB{DB: db}, nil
+	rdb := &relational.DB{DB: db}
+	rdb.SetLogger(cfg.Logger)
+
+	return rdb, nil
 }
@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 
-	"github.com/rs/zerolog/log"
 	"github.com/uptrace/bun"
 	"github.com/vanclief/ez"
 )
@@ -28,7 +27,7 @@ func CreateDatabase(cfg ConnectionConfig) error {
 		return ez.Wrap(err)
 	}
 
-	log.Info().
+	cfg.logger().Info().
 		Str("Host", cfg.Host).
 		Str("Username", cfg.Username).
 		Str("Database", cfg.Database).
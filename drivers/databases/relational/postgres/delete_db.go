@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 
-	"github.com/rs/zerolog/log"
 	"github.com/uptrace/bun"
 	"github.com/vanclief/ez"
 )
@@ -29,7 +28,7 @@ func DeleteDatabase(cfg ConnectionConfig) error {
 		return ez.Wrap(err)
 	}
 
-	log.Info().
+	cfg.logger().Info().
 		Str("Host", cfg.Host).
 		Str("Username", cfg.Username).
 		Str("Database", cfg.Database).
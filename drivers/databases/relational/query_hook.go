@@ -0,0 +1,76 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/logger"
+)
+
+// QueryHook is a bun.QueryHook logging queries to a logger.Logger, in place of bundebug
+// writing them to stderr. Failed queries are logged as errors, sql.ErrNoRows and
+// sql.ErrTxDone aside; with verbose every other query is logged at info level.
+//
+// The logged query has its arguments interpolated, and they are not redacted since
+// they aren't fields of their own: use WithoutQueryArgs when they may hold secrets or
+// personal data.
+type QueryHook struct {
+	log      logger.Logger
+	verbose  bool
+	hideArgs bool
+}
+
+// QueryHookOption configures the QueryHook.
+type QueryHookOption func(*QueryHook)
+
+// WithoutQueryArgs logs queries with their placeholders instead of their argument values.
+func WithoutQueryArgs() QueryHookOption {
+	return func(h *QueryHook) {
+		h.hideArgs = true
+	}
+}
+
+// NewQueryHook creates a QueryHook logging to log, logger.Default if nil.
+func NewQueryHook(log logger.Logger, verbose bool, opts ...QueryHookOption) *QueryHook {
+	if log == nil {
+		log = logger.Default()
+	}
+
+	h := &QueryHook{log: log, verbose: verbose}
+	for _, o := range opts {
+		o(h)
+	}
+
+	return h
+}
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	var e logger.Event
+	switch {
+	case event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) && !errors.Is(event.Err, sql.ErrTxDone):
+		e = h.log.Ctx(ctx).Error().Err(event.Err)
+	case h.verbose:
+		e = h.log.Ctx(ctx).Info()
+	default:
+		return
+	}
+
+	query := event.Query
+	if h.hideArgs {
+		query = event.QueryTemplate
+	}
+
+	e.Str("operation", event.Operation()).
+		Dur("duration", time.Since(event.StartTime)).
+		Str("query", query).
+		Msg("Database query")
+}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
@@ -0,0 +1,62 @@
+package relational
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/vanclief/compose/components/logger/logtest"
+)
+
+func TestQueryHook(t *testing.T) {
+	query := "SELECT * FROM users WHERE email = 'ana@example.com'"
+	template := "SELECT * FROM users WHERE email = ?"
+
+	testCases := []struct {
+		name     string
+		err      error
+		verbose  bool
+		hideArgs bool
+		level    logtest.Level
+		query    string
+	}{
+		{"verbose", nil, true, false, logtest.LevelInfo, query},
+		{"not verbose", nil, false, false, 0, ""},
+		{"failed", errors.New("connection reset"), false, false, logtest.LevelError, query},
+		{"no rows", sql.ErrNoRows, true, false, logtest.LevelInfo, query},
+		{"no rows, not verbose", sql.ErrNoRows, false, false, 0, ""},
+		{"without args", nil, true, true, logtest.LevelInfo, template},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := logtest.New()
+			rec.SetLevel(logtest.LevelInfo)
+
+			var opts []QueryHookOption
+			if tc.hideArgs {
+				opts = append(opts, WithoutQueryArgs())
+			}
+
+			h := NewQueryHook(rec, tc.verbose, opts...)
+			h.AfterQuery(context.Background(), &bun.QueryEvent{
+				Query:         query,
+				QueryTemplate: template,
+				StartTime:     time.Now(),
+				Err:           tc.err,
+			})
+
+			if tc.query == "" {
+				require.Empty(t, rec.Entries())
+				return
+			}
+
+			require.Len(t, rec.Entries(), 1, rec.String())
+			require.True(t, rec.HasEntry(tc.level, "Database query", "operation", "SELECT", "query", tc.query), rec.String())
+		})
+	}
+}
@@ -10,9 +10,11 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/vanclief/compose/components/logger"
 	"github.com/vanclief/ez"
 )
 
+// WithZerolog sends the global zerolog logger, and so logger.Default, to Promtail.
 func WithZerolog(params *WithPromtailParams) error {
 	// Setup PromTail
 	writer, err := NewWriter(params)
@@ -20,7 +22,26 @@ func WithZerolog(params *WithPromtailParams) error {
 		return ez.Wrap(err)
 	}
 
-	// Setup the logger
+	log.Logger = log.Output(consoleWriter(writer))
+	logConfig(logger.Default(), params)
+
+	return nil
+}
+
+// NewLogger returns a logger.Logger sending to Promtail, leaving the global zerolog logger untouched.
+func NewLogger(params *WithPromtailParams) (logger.Logger, error) {
+	writer, err := NewWriter(params)
+	if err != nil {
+		return nil, ez.Wrap(err)
+	}
+
+	l := logger.NewZero(zerolog.New(consoleWriter(writer)).With().Timestamp().Logger())
+	logConfig(l, params)
+
+	return l, nil
+}
+
+func consoleWriter(writer io.Writer) zerolog.ConsoleWriter {
 	output := zerolog.ConsoleWriter{Out: writer}
 
 	output.FormatMessage = func(i interface{}) string {
@@ -32,9 +53,11 @@ func WithZerolog(params *WithPromtailParams) error {
 		}
 	}
 
-	log.Logger = log.Output(output)
+	return output
+}
 
-	log.Info().
+func logConfig(l logger.Logger, params *WithPromtailParams) {
+	l.Info().
 		Str("App", params.App).
 		Str("Environment", params.Environment).
 		Str("Host", params.PromtailHost).
@@ -42,8 +65,6 @@ func WithZerolog(params *WithPromtailParams) error {
 		Int("Timeout MS", params.PromtailTimeoutMS).
 		Bool("Enabled", params.PromtailEnabled).
 		Msg("Promtail Config")
-
-	return nil
 }
 
 func NewWriter(params *WithPromtailParams) (io.Writer, error) {